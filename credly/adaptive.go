@@ -0,0 +1,116 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithAdaptiveConcurrency makes the client adapt the number of requests it
+// sends in parallel to the health of the Credly API, so that bulk jobs back off
+// during incidents instead of piling on.
+//
+// Up to maxConcurrency requests are allowed in flight. Each response slower
+// than slowThreshold, each 429 or 5xx response and each transport error cuts
+// the allowed concurrency by a quarter, down to a single request. Each healthy
+// response raises it gradually back to maxConcurrency. Requests over the limit
+// wait for a slot, or fail with the error of their context. Retries count as
+// separate requests. A maxConcurrency of zero or less disables the controller,
+// which is the default.
+//
+// Clients returned by Env share the controller of their parent.
+func WithAdaptiveConcurrency(maxConcurrency int, slowThreshold time.Duration) Option {
+	return func(c *Client) {
+		if maxConcurrency <= 0 {
+			c.adaptive = nil
+			return
+		}
+		c.adaptive = &adaptiveLimiter{
+			max:           float64(maxConcurrency),
+			limit:         float64(maxConcurrency),
+			slowThreshold: slowThreshold,
+			changed:       make(chan struct{}),
+		}
+	}
+}
+
+// adaptiveDecrease is the factor applied to the concurrency limit of an
+// adaptiveLimiter when a request shows that the API is struggling.
+const adaptiveDecrease = 0.75
+
+// adaptiveLimiter bounds the number of requests in flight, adjusting the bound
+// with additive increase and multiplicative decrease based on the outcome of
+// each request.
+type adaptiveLimiter struct {
+	max           float64
+	slowThreshold time.Duration
+
+	mu       sync.Mutex
+	limit    float64
+	inflight int
+
+	// changed is closed and replaced whenever a slot is released or the limit
+	// changes, waking up the requests waiting for a slot.
+	changed chan struct{}
+}
+
+// acquire blocks until a request is allowed, or ctx is done.
+func (a *adaptiveLimiter) acquire(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		a.mu.Lock()
+		if a.inflight < int(a.limit) {
+			a.inflight++
+			a.mu.Unlock()
+			return nil
+		}
+		changed := a.changed
+		a.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// release frees the slot of a request that took d to complete, adjusting the
+// limit according to its outcome.
+func (a *adaptiveLimiter) release(resp *http.Response, err error, d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.inflight--
+	switch {
+	case errors.Is(err, context.Canceled):
+		// Abandoned by the caller, which says nothing about the API
+	case err != nil || retryableStatus(resp.StatusCode) || (a.slowThreshold > 0 && d > a.slowThreshold):
+		a.limit = max(1, a.limit*adaptiveDecrease)
+	default:
+		// Grow by about one request per round of requests at the current limit
+		a.limit = min(a.max, a.limit+1/a.limit)
+	}
+
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	client := NewClient("test-token", "org-123", WithAdaptiveConcurrency(4, 100*time.Millisecond))
+	a := client.adaptive
+	ctx := context.Background()
+
+	// Failures and slow responses cut the limit, down to a single request
+	for _, outcome := range []struct {
+		resp *http.Response
+		err  error
+		d    time.Duration
+	}{
+		{resp: &http.Response{StatusCode: http.StatusServiceUnavailable}},
+		{resp: &http.Response{StatusCode: http.StatusTooManyRequests}},
+		{err: errors.New("connection reset")},
+		{resp: &http.Response{StatusCode: http.StatusOK}, d: time.Second},
+		{resp: &http.Response{StatusCode: http.StatusOK}, d: time.Second},
+		{resp: &http.Response{StatusCode: http.StatusOK}, d: time.Second},
+	} {
+		assert.NoError(t, a.acquire(ctx))
+		a.release(outcome.resp, outcome.err, outcome.d)
+	}
+	assert.Equal(t, 1.0, a.limit)
+
+	// Requests abandoned by the caller don't count
+	assert.NoError(t, a.acquire(ctx))
+	a.release(nil, context.Canceled, 0)
+	assert.Equal(t, 1.0, a.limit)
+
+	// Healthy responses raise it back to the maximum
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, a.acquire(ctx))
+		a.release(&http.Response{StatusCode: http.StatusOK}, nil, time.Millisecond)
+	}
+	assert.Equal(t, 4.0, a.limit)
+	assert.Zero(t, a.inflight)
+}
+
+func TestAdaptiveLimiter_Wait(t *testing.T) {
+	a := &adaptiveLimiter{max: 1, limit: 1, changed: make(chan struct{})}
+
+	assert.NoError(t, a.acquire(context.Background()))
+
+	// A request over the limit waits for a slot, or for its context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, a.acquire(ctx), context.DeadlineExceeded)
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- a.acquire(context.Background())
+	}()
+	a.release(&http.Response{StatusCode: http.StatusOK}, nil, 0)
+
+	assert.NoError(t, <-acquired)
+}
+
+func TestWithAdaptiveConcurrency(t *testing.T) {
+	var inflight, peak int32
+	client := NewClient("test-token", "org-123", WithAdaptiveConcurrency(2, 0))
+	client.HTTPClient = HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
+			_, err := client.Do(req)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak, int32(2))
+
+	// Disabled by default
+	assert.Nil(t, NewClient("test-token", "org-123").adaptive)
+	assert.Nil(t, NewClient("test-token", "org-123", WithAdaptiveConcurrency(0, time.Second)).adaptive)
+}
@@ -0,0 +1,225 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AuthRefreshFunc returns a new API token, e.g. read from a secret manager
+// after the token was rotated, see WithAuthRefresh.
+type AuthRefreshFunc func(ctx context.Context) (token string, err error)
+
+// WithAuthRefresh makes the Client call refresh when Credly rejects its
+// credentials with 401 or 403, and resend the request once with the returned
+// token. The new token is used for all subsequent requests. Concurrent requests
+// rejected with the same token only trigger one refresh.
+//
+// If refresh fails, the request fails with an error matching ErrUnauthorized
+// and wrapping the refresh error. Clients returned by Env don't refresh their
+// credentials.
+func WithAuthRefresh(refresh AuthRefreshFunc) Option {
+	return func(c *Client) {
+		c.auth = &authRefresher{refresh: refresh}
+	}
+}
+
+// authRefresher holds the token obtained by the last refresh.
+type authRefresher struct {
+	refresh AuthRefreshFunc
+
+	mu sync.Mutex
+
+	// token is the base64-encoded refreshed token, or "" if none was obtained yet.
+	token string
+}
+
+// Token is an OAuth2 bearer token, see TokenSource.
+type Token struct {
+	AccessToken string
+
+	// Expiry is when the token expires, or zero if it doesn't.
+	Expiry time.Time
+}
+
+// TokenSource returns bearer tokens, e.g. by running an OAuth2 client
+// credentials flow, see WithTokenSource.
+type TokenSource interface {
+	Token(ctx context.Context) (Token, error)
+}
+
+// tokenExpiryDelta is how long before its expiry a bearer token is replaced,
+// so that it doesn't expire while a request is in flight.
+const tokenExpiryDelta = time.Minute
+
+// WithTokenSource makes the Client authenticate with OAuth2 bearer tokens
+// obtained from src instead of the API token passed to NewClient, which can
+// then be empty.
+//
+// A token is reused until it is about to expire. Concurrent requests share the
+// same token and only trigger one call to src. If src fails, the request fails
+// with an error matching ErrUnauthorized and wrapping the error from src.
+// Clients returned by Env use the basic token of their environment.
+func WithTokenSource(src TokenSource) Option {
+	return func(c *Client) {
+		c.tokens = &tokenCache{src: src}
+	}
+}
+
+// tokenCache holds the last token obtained from a TokenSource.
+type tokenCache struct {
+	src TokenSource
+
+	mu    sync.Mutex
+	token Token
+}
+
+// get returns a valid bearer token, obtaining a new one if needed.
+func (t *tokenCache) get(ctx context.Context, now time.Time) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token.AccessToken != "" && (t.token.Expiry.IsZero() || now.Add(tokenExpiryDelta).Before(t.token.Expiry)) {
+		return t.token.AccessToken, nil
+	}
+
+	token, err := t.src.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("token source returned an empty token")
+	}
+	t.token = token
+
+	return token.AccessToken, nil
+}
+
+// authorization returns the value of the Authorization header.
+func (c *Client) authorization(ctx context.Context) (string, error) {
+	if c.tokens != nil {
+		token, err := c.tokens.get(ctx, time.Now())
+		if err != nil {
+			return "", fmt.Errorf("[credly] %w: Failed to obtain a bearer token: %w", ErrUnauthorized, err)
+		}
+		return "Bearer " + token, nil
+	}
+	if c.auth != nil {
+		c.auth.mu.Lock()
+		defer c.auth.mu.Unlock()
+		if c.auth.token != "" {
+			return "Basic " + c.auth.token, nil
+		}
+	}
+	return "Basic " + c.authToken, nil
+}
+
+// OrganizationID returns the ID of the organization the Client sends its
+// requests for, e.g. to log alongside TokenFingerprint when diagnosing a client
+// configured for the wrong organization.
+func (c *Client) OrganizationID() string {
+	return c.OrganizationId
+}
+
+// TokenFingerprint returns a short fingerprint of the API token the Client
+// currently authenticates with, e.g. to log which credentials are in use
+// alongside OrganizationID without leaking the token. It is the first 8 bytes
+// of the SHA-256 hash of the token, hex-encoded, and follows refreshes made
+// with WithAuthRefresh. It returns "" when the Client uses WithTokenSource.
+func (c *Client) TokenFingerprint() string {
+	if c.tokens != nil {
+		return ""
+	}
+
+	token := c.authToken
+	if c.auth != nil {
+		c.auth.mu.Lock()
+		if c.auth.token != "" {
+			token = c.auth.token
+		}
+		c.auth.mu.Unlock()
+	}
+
+	// authToken holds the encoded token, decode it so the fingerprint can be
+	// computed independently from the raw token
+	raw, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return ""
+	}
+	h := sha256.Sum256(bytes.TrimSuffix(raw, []byte("|")))
+
+	return hex.EncodeToString(h[:8])
+}
+
+// sendWithAuthRefresh sends the request and, if its credentials are rejected,
+// refreshes them and resends the request once, see WithAuthRefresh.
+func (c *Client) sendWithAuthRefresh(req *http.Request) (*http.Response, error) {
+	resp, err := c.sendWithRetry(req)
+	if err != nil || c.auth == nil {
+		return resp, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return resp, nil
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if err := c.auth.refreshFrom(req.Context(), req.Header.Get("Authorization")); err != nil {
+		return nil, fmt.Errorf("[credly] %w: Failed to refresh credentials: %w", ErrUnauthorized, err)
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+
+	return c.sendWithRetry(req)
+}
+
+// refreshFrom obtains a new token, unless the token rejected in the failed
+// Authorization header was already replaced by a concurrent refresh.
+func (a *authRefresher) refreshFrom(ctx context.Context, failed string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && "Basic "+a.token != failed {
+		return nil
+	}
+
+	token, err := a.refresh(ctx)
+	if err != nil {
+		return err
+	}
+	a.token = encodeToken(token)
+
+	return nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUnauthorized(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", mock.Anything).Return(&http.Response{
+			StatusCode: status,
+			Body:       io.NopCloser(bytes.NewBufferString("")),
+		}, nil)
+
+		_, err := client.GetBadgeTemplate("template-123")
+
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	}
+}
+
+func TestWithAuthRefresh(t *testing.T) {
+	refreshes := 0
+	refresh := func(ctx context.Context) (string, error) {
+		refreshes++
+		return "rotated-token", nil
+	}
+
+	mockClient := new(MockHTTPClient)
+	client := NewClient("expired-token", "org-123", WithAuthRefresh(refresh))
+	client.HTTPClient = mockClient
+
+	withToken := func(token string) interface{} {
+		return mock.MatchedBy(func(req *http.Request) bool {
+			return req.Header.Get("Authorization") == "Basic "+encodeToken(token)
+		})
+	}
+
+	// The POST body must be resent in full with the new token
+	var bodies []string
+	recordBody := func(args mock.Arguments) {
+		body, _ := io.ReadAll(args.Get(0).(*http.Request).Body)
+		bodies = append(bodies, string(body))
+	}
+
+	mockClient.On("Do", withToken("expired-token")).Run(recordBody).Return(&http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+	mockClient.On("Do", withToken("rotated-token")).Run(recordBody).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "badge-123"}}`)),
+	}, nil).Once()
+	mockClient.On("Do", withToken("rotated-token")).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "template-123"}}`)),
+	}, nil).Once()
+
+	badge, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "badge-123", badge.Id)
+	assert.Len(t, bodies, 2)
+	assert.Equal(t, bodies[0], bodies[1])
+
+	// Subsequent requests use the new token
+	_, err = client.GetBadgeTemplate("template-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, refreshes)
+	mockClient.AssertExpectations(t)
+}
+
+func TestWithAuthRefresh_StillUnauthorized(t *testing.T) {
+	refresh := func(ctx context.Context) (string, error) {
+		return "also-invalid", nil
+	}
+
+	mockClient := new(MockHTTPClient)
+	client := NewClient("expired-token", "org-123", WithAuthRefresh(refresh))
+	client.HTTPClient = mockClient
+
+	for i := 0; i < 2; i++ {
+		mockClient.On("Do", mock.Anything).Return(&http.Response{
+			StatusCode: http.StatusForbidden,
+			Body:       io.NopCloser(bytes.NewBufferString("")),
+		}, nil).Once()
+	}
+
+	_, err := client.GetBadgeTemplate("template-123")
+
+	// The request is only resent once
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	mockClient.AssertExpectations(t)
+}
+
+func TestWithAuthRefresh_RefreshFails(t *testing.T) {
+	errSecrets := errors.New("secret manager unavailable")
+	refresh := func(ctx context.Context) (string, error) {
+		return "", errSecrets
+	}
+
+	mockClient := new(MockHTTPClient)
+	client := NewClient("expired-token", "org-123", WithAuthRefresh(refresh))
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	_, err := client.GetBadgeTemplate("template-123")
+
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.ErrorIs(t, err, errSecrets)
+	mockClient.AssertExpectations(t)
+}
+
+// countingTokenSource returns tokens with the configured lifetime, numbered by call.
+type countingTokenSource struct {
+	mu       sync.Mutex
+	calls    int
+	lifetime time.Duration
+	err      error
+}
+
+func (s *countingTokenSource) Token(ctx context.Context) (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return Token{}, s.err
+	}
+	s.calls++
+	token := Token{AccessToken: fmt.Sprintf("token-%d", s.calls)}
+	if s.lifetime != 0 {
+		token.Expiry = time.Now().Add(s.lifetime)
+	}
+	return token, nil
+}
+
+func TestWithTokenSource(t *testing.T) {
+	src := &countingTokenSource{lifetime: time.Hour}
+
+	var mu sync.Mutex
+	var authorizations []string
+	client := NewClient("", "org-123", WithTokenSource(src))
+	client.HTTPClient = HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		authorizations = append(authorizations, req.Header.Get("Authorization"))
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "template-123"}}`)),
+		}, nil
+	})
+
+	// Concurrent requests share a single token
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetBadgeTemplate("template-123")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, src.calls)
+	assert.Len(t, authorizations, 10)
+	for _, a := range authorizations {
+		assert.Equal(t, "Bearer token-1", a)
+	}
+}
+
+func TestWithTokenSource_Expiry(t *testing.T) {
+	// Tokens expiring within tokenExpiryDelta are replaced on every request
+	src := &countingTokenSource{lifetime: tokenExpiryDelta / 2}
+	cache := &tokenCache{src: src}
+
+	token, err := cache.get(context.Background(), time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	token, err = cache.get(context.Background(), time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "token-2", token)
+
+	// Tokens without expiry are kept
+	src.lifetime = 0
+	cache = &tokenCache{src: src}
+
+	token, _ = cache.get(context.Background(), time.Now())
+	assert.Equal(t, "token-3", token)
+	token, _ = cache.get(context.Background(), time.Now().Add(24*time.Hour))
+	assert.Equal(t, "token-3", token)
+}
+
+func TestWithTokenSource_Failure(t *testing.T) {
+	errIdP := errors.New("identity provider unavailable")
+	src := &countingTokenSource{err: errIdP}
+
+	mockClient := new(MockHTTPClient)
+	client := NewClient("", "org-123", WithTokenSource(src))
+	client.HTTPClient = mockClient
+
+	_, err := client.GetBadgeTemplate("template-123")
+
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.ErrorIs(t, err, errIdP)
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestOrganizationID(t *testing.T) {
+	client := NewClient("test-token", "org-123", WithEnvironment("sandbox", Environment{Token: "sandbox-token", OrganizationId: "org-456"}))
+
+	assert.Equal(t, "org-123", client.OrganizationID())
+
+	sandbox, err := client.Env("sandbox")
+	assert.NoError(t, err)
+	assert.Equal(t, "org-456", sandbox.OrganizationID())
+}
+
+func TestTokenFingerprint(t *testing.T) {
+	client := NewClient("test-token", "org-123")
+
+	fingerprint := client.TokenFingerprint()
+
+	// The fingerprint can be computed from the raw token, but doesn't reveal it
+	h := sha256.Sum256([]byte("test-token"))
+	assert.Equal(t, hex.EncodeToString(h[:8]), fingerprint)
+	assert.NotContains(t, fingerprint, "test-token")
+	assert.NotEqual(t, fingerprint, NewClient("other-token", "org-123").TokenFingerprint())
+
+	// Refreshed tokens are reflected
+	client.auth = &authRefresher{token: encodeToken("rotated-token")}
+	h = sha256.Sum256([]byte("rotated-token"))
+	assert.Equal(t, hex.EncodeToString(h[:8]), client.TokenFingerprint())
+
+	assert.Empty(t, NewClient("", "org-123", WithTokenSource(&countingTokenSource{})).TokenFingerprint())
+}
@@ -16,23 +16,65 @@ package credly
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/mail"
 	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-// issueBadgeResponse represents the response structure when a badge is issued.
-// see https://www.credly.com/docs/issued_badges
-type issueBadgeResponse struct {
-	Data BadgeInfo `json:"data"`
+// timeLayout is the layout of the timestamps sent to Credly.
+const timeLayout = "2006-01-02 15:04:05 -0700"
+
+// credlyTimeLayouts are the layouts of the timestamps received from Credly,
+// tried in order. Timestamps without a time zone are in UTC.
+var credlyTimeLayouts = []string{
+	time.RFC3339,
+	timeLayout,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02T15:04:05",
+	time.DateTime,
+	time.DateOnly,
 }
 
-// getBadgesResponse represents the response structure when fetching multiple badges.
-type getBadgesResponse struct {
-	Data []BadgeInfo `json:"data"`
+// credlyTime is a timestamp received from Credly. Unlike time.Time, it decodes
+// any of the layouts in credlyTimeLayouts, e.g. date-only values. Null and
+// empty values decode to the zero time.
+type credlyTime time.Time
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *credlyTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("[credly.BadgeInfo] Invalid timestamp: %s", data)
+	}
+	if s == "" {
+		*t = credlyTime{}
+		return nil
+	}
+
+	for _, layout := range credlyTimeLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			*t = credlyTime(parsed)
+			return nil
+		}
+	}
+	return fmt.Errorf("[credly.BadgeInfo] Unknown timestamp format: %q", s)
 }
 
 // BadgeInfo represents the details of an issued badge.
@@ -43,6 +85,27 @@ type BadgeInfo struct {
 	IssuedAt time.Time `json:"issued_at"`
 	State    string    `json:"state"`
 
+	// ExpiresAt is the expiration date of the badge, or the zero time if it doesn't expire.
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// AcceptedAt is the date the recipient accepted the badge, or the zero time if they haven't.
+	AcceptedAt time.Time `json:"accepted_at"`
+
+	// RevokedAt is the date the badge was revoked, or the zero time if it wasn't.
+	RevokedAt time.Time `json:"revoked_at"`
+
+	// UpdatedAt is the date the badge was last changed, e.g. issued, accepted or revoked.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// RevocationReason is the reason given when the badge was revoked.
+	RevocationReason string `json:"revocation_reason"`
+
+	// Public reports whether the badge is visible on the recipient's public profile.
+	Public bool `json:"public"`
+
+	// Issuer is the organization that issued the badge.
+	Issuer Issuer `json:"issuer"`
+
 	Image struct {
 		Url string `json:"url"`
 	} `json:"image"`
@@ -56,38 +119,338 @@ type BadgeInfo struct {
 		LastName  string `json:"last_name"`
 		Url       string `json:"url"`
 	} `json:"user"`
+
+	// CustomFields holds the values of the issuer-defined fields configured on the template.
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+
+	// RecipientAttributes holds the issuer's metadata about the recipient, see IssueOptions.
+	RecipientAttributes map[string]string `json:"recipient_attributes,omitempty"`
+
+	// DryRun is set on the synthetic badges returned instead of issuing badges
+	// in dry-run mode, see WithDryRun. It is never set on badges from Credly.
+	DryRun bool `json:"-"`
+
+	// Extra holds the raw values of the fields returned by Credly that aren't
+	// mapped to BadgeInfo fields, keyed by field name, or nil if there are none.
+	// It gives access to fields added by Credly before they are supported.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// badgeInfoFields is the set of JSON field names mapped to BadgeInfo fields.
+var badgeInfoFields = sync.OnceValue(func() map[string]bool {
+	fields := map[string]bool{}
+	t := reflect.TypeOf(BadgeInfo{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+})
+
+// UnmarshalJSON implements the json.Unmarshaler interface, collecting the
+// unmapped fields in Extra. Timestamps are accepted in any of the layouts
+// Credly is known to use, see credlyTime.
+func (b *BadgeInfo) UnmarshalJSON(data []byte) error {
+	type badgeInfo BadgeInfo
+	var aux struct {
+		badgeInfo
+		IssuedAt   credlyTime `json:"issued_at"`
+		ExpiresAt  credlyTime `json:"expires_at"`
+		AcceptedAt credlyTime `json:"accepted_at"`
+		RevokedAt  credlyTime `json:"revoked_at"`
+		UpdatedAt  credlyTime `json:"updated_at"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	decoded := aux.badgeInfo
+	decoded.IssuedAt = time.Time(aux.IssuedAt)
+	decoded.ExpiresAt = time.Time(aux.ExpiresAt)
+	decoded.AcceptedAt = time.Time(aux.AcceptedAt)
+	decoded.RevokedAt = time.Time(aux.RevokedAt)
+	decoded.UpdatedAt = time.Time(aux.UpdatedAt)
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for name, value := range raw {
+		if badgeInfoFields()[name] {
+			continue
+		}
+		if decoded.Extra == nil {
+			decoded.Extra = map[string]json.RawMessage{}
+		}
+		decoded.Extra[name] = value
+	}
+
+	*b = BadgeInfo(decoded)
+	return nil
+}
+
+// Issuer identifies the organization that issued a badge.
+type Issuer struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	VanityUrl string `json:"vanity_url"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Credly nests the
+// issuing organization in the entities of the issuer object, along with any
+// other organization involved, such as an authorizing one: the primary entity
+// is used, falling back to the first. An object holding the fields directly is
+// also accepted.
+func (i *Issuer) UnmarshalJSON(data []byte) error {
+	type issuer Issuer
+	var raw struct {
+		issuer
+		Entities []struct {
+			Primary bool   `json:"primary"`
+			Entity  issuer `json:"entity"`
+		} `json:"entities"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*i = Issuer(raw.issuer)
+	if i.Id != "" || len(raw.Entities) == 0 {
+		return nil
+	}
+
+	*i = Issuer(raw.Entities[0].Entity)
+	for _, e := range raw.Entities {
+		if e.Primary {
+			*i = Issuer(e.Entity)
+			break
+		}
+	}
+	return nil
+}
+
+// Badge states reported by Credly in BadgeInfo.State.
+const (
+	BadgeStateAccepted = "accepted"
+	BadgeStateExpired  = "expired"
+	BadgeStateIssued   = "issued"
+	BadgeStatePending  = "pending"
+	BadgeStateRejected = "rejected"
+	BadgeStateRevoked  = "revoked"
+)
+
+// badgeStates is the set of states accepted by GetBadgesByState.
+var badgeStates = map[string]bool{
+	BadgeStateAccepted: true,
+	BadgeStateExpired:  true,
+	BadgeStateIssued:   true,
+	BadgeStatePending:  true,
+	BadgeStateRejected: true,
+	BadgeStateRevoked:  true,
+}
+
+// IsExpired reports whether the badge has an expiration date that is before now.
+func (b BadgeInfo) IsExpired(now time.Time) bool {
+	return !b.ExpiresAt.IsZero() && b.ExpiresAt.Before(now)
+}
+
+// IsActive reports whether the badge can currently be used by its recipient:
+// it has been accepted, which excludes pending, rejected and revoked badges,
+// and it isn't expired at now.
+func (b BadgeInfo) IsActive(now time.Time) bool {
+	return b.State == BadgeStateAccepted && !b.IsExpired(now)
+}
+
+// IssueOptions holds optional settings for issuing a badge.
+//
+// There is no option to place the badge in one of the recipient's profile
+// collections: these are curated by recipients on their Credly profile and are
+// not exposed by the organization API, whether or not the badge was accepted.
+type IssueOptions struct {
+	// CustomFields holds values for the issuer-defined fields configured on the
+	// badge template (e.g. exam score, grade), keyed by field name.
+	CustomFields map[string]string
+
+	// IssuedAt backdates the badge, e.g. when migrating historical
+	// certifications. The zero value means the current time.
+	IssuedAt time.Time
+
+	// ExpiresAt is the date the badge expires. The zero value issues a badge
+	// that doesn't expire.
+	ExpiresAt time.Time
+
+	// Evidence is attached to the badge to document how it was earned.
+	Evidence []Evidence
+
+	// RecipientAttributes holds metadata about the recipient stored with the
+	// badge, e.g. an employee ID or department, keyed by attribute name. Unlike
+	// CustomFields, they don't need to be configured on the template.
+	RecipientAttributes map[string]string
+
+	// IdempotencyKey is sent in the Idempotency-Key header so that Credly
+	// issues the badge only once for all requests carrying the same key, e.g.
+	// when a request is retried after a timeout. DefaultRetryPolicy only retries
+	// an issuance that failed with a 5xx status code if it carries a key. See
+	// IdempotencyKeyFor for a key derived from the template and recipient. IssueBadges sends a distinct
+	// key per recipient, derived from this one and the recipient's email.
+	IdempotencyKey string
+
+	// SuppressNotification stops Credly from emailing the recipient about the
+	// badge, e.g. when backfilling historical certifications with IssuedAt.
+	SuppressNotification bool
+
+	// UserId issues the badge to an existing Credly user by ID rather than by
+	// email, e.g. when the email on their Credly account differs from the one
+	// the organization has. The email passed along must then be empty.
+	UserId string
+}
+
+// Evidence types supported by Credly.
+const (
+	EvidenceId   = "IdEvidence"
+	EvidenceText = "TextEvidence"
+	EvidenceUrl  = "UrlEvidence"
+)
+
+// Evidence represents a piece of evidence attached to an issued badge, such as
+// a link to an exam result.
+// see https://www.credly.com/docs/issued_badges
+type Evidence struct {
+	Type        string `json:"type"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Url         string `json:"url,omitempty"`
 }
 
 // IssueBadge issues a new badge to a user based on their email and personal details.
 //
+// Surrounding whitespace is trimmed from the email and names. A malformed email
+// is rejected with ErrInvalidEmail, and empty names are rejected, without
+// sending the request.
+//
 // templateId: The ID of the badge template to be issued.
 // email: The recipient's email address.
 // firstName: The recipient's first name.
 // lastName: The recipient's last name.
 // Returns: BadgeInfo representing the issued badge, or an error if the operation fails.
 func (c *Client) IssueBadge(templateId, email, firstName, lastName string) (i BadgeInfo, err error) {
-	url := fmt.Sprintf("https://api.credly.com/v1/organizations/%s/badges", c.OrganizationId)
+	return c.IssueBadgeWithOptions(templateId, email, firstName, lastName, IssueOptions{})
+}
 
-	now := time.Now()
-	issuedAt := now.Format("2006-01-02 15:04:05 -0700")
+// IssueBadgeAt issues a new badge to a user, dated at issuedAt rather than the current time.
+//
+// templateId: The ID of the badge template to be issued.
+// email: The recipient's email address.
+// firstName: The recipient's first name.
+// lastName: The recipient's last name.
+// issuedAt: The time the badge was earned.
+// Returns: BadgeInfo representing the issued badge, or an error if the operation fails.
+func (c *Client) IssueBadgeAt(templateId, email, firstName, lastName string, issuedAt time.Time) (i BadgeInfo, err error) {
+	return c.IssueBadgeWithOptions(templateId, email, firstName, lastName, IssueOptions{IssuedAt: issuedAt})
+}
+
+// IssueBadgeWithOptions issues a new badge to a user, applying the optional settings in opts.
+//
+// templateId: The ID of the badge template to be issued.
+// email: The recipient's email address, or empty if opts.UserId is set.
+// firstName: The recipient's first name.
+// lastName: The recipient's last name.
+// opts: Optional issuance settings.
+// Returns: BadgeInfo representing the issued badge, or an error if the operation fails.
+func (c *Client) IssueBadgeWithOptions(templateId, email, firstName, lastName string, opts IssueOptions) (i BadgeInfo, err error) {
+	return c.issueBadge(context.Background(), templateId, email, firstName, lastName, opts)
+}
+
+// issueBadge issues a new badge to a user, applying the optional settings in opts.
+func (c *Client) issueBadge(ctx context.Context, templateId, email, firstName, lastName string, opts IssueOptions) (i BadgeInfo, err error) {
+	userId := strings.TrimSpace(opts.UserId)
+	if userId != "" {
+		if strings.TrimSpace(email) != "" {
+			return i, fmt.Errorf("[credly.IssueBadge] Exactly one of email or user ID must be set")
+		}
+		firstName, lastName, err = normalizeNames("credly.IssueBadge", firstName, lastName)
+	} else {
+		email, firstName, lastName, err = normalizeRecipient("credly.IssueBadge", email, firstName, lastName)
+	}
+	if err != nil {
+		return i, err
+	}
+
+	url := fmt.Sprintf("%s/organizations/%s/badges", c.baseURL(), c.OrganizationId)
+
+	issuedAt := opts.IssuedAt
+	if issuedAt.IsZero() {
+		issuedAt = time.Now()
+	}
 
 	params := map[string]interface{}{
 		"badge_template_id":    templateId,
-		"recipient_email":      email,
 		"issued_to_first_name": firstName,
 		"issued_to_last_name":  lastName,
-		"issued_at":            issuedAt,
+		"issued_at":            issuedAt.In(c.timezone()).Format(timeLayout),
+	}
+
+	if userId != "" {
+		params["user_id"] = userId
+	} else {
+		params["recipient_email"] = email
+	}
+
+	if !opts.ExpiresAt.IsZero() {
+		params["expires_at"] = opts.ExpiresAt.In(c.timezone()).Format(timeLayout)
+	}
+
+	if len(opts.CustomFields) > 0 {
+		for k := range opts.CustomFields {
+			if k == "" {
+				return i, fmt.Errorf("[credly.IssueBadge] Custom field names must not be empty")
+			}
+		}
+		params["custom_fields"] = opts.CustomFields
+	}
+
+	if len(opts.Evidence) > 0 {
+		for _, e := range opts.Evidence {
+			if e.Type == "" {
+				return i, fmt.Errorf("[credly.IssueBadge] Evidence type must not be empty")
+			}
+		}
+		params["evidence"] = opts.Evidence
+	}
+
+	if len(opts.RecipientAttributes) > 0 {
+		for k := range opts.RecipientAttributes {
+			if k == "" {
+				return i, fmt.Errorf("[credly.IssueBadge] Recipient attribute names must not be empty")
+			}
+		}
+		params["recipient_attributes"] = opts.RecipientAttributes
 	}
+
+	if opts.SuppressNotification {
+		params["suppress_badge_notification_email"] = true
+	}
+
 	reqBody, err := json.Marshal(params)
 	if err != nil {
 		return i, fmt.Errorf("[credly.IssueBadge] Failed to marshal parameters: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
+	req, err := http.NewRequestWithContext(withOperation(ctx, "credly.IssueBadge"), "POST", url, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return i, err
 	}
 
+	if opts.IdempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, opts.IdempotencyKey)
+	}
+
+	if c.dryRun {
+		return dryRunBadge(templateId, email, firstName, lastName, issuedAt, opts), nil
+	}
+
 	resp, err := c.Do(req)
 	if err != nil {
 		return i, err
@@ -96,37 +459,216 @@ func (c *Client) IssueBadge(templateId, email, firstName, lastName string) (i Ba
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUnprocessableEntity {
-		// Contact already has badge
-		return i, fmt.Errorf(ErrBadgeAlreadyIssued)
+		apiErr := newAPIError("credly.IssueBadge", resp)
+		// Credly also uses 422 for invalid parameters, such as an invalid email
+		// or an unknown template, which are told apart by the error message.
+		if apiErr.Message == "" || strings.Contains(strings.ToLower(apiErr.Message), "already") {
+			// Contact already has badge
+			if apiErr.Message == "" {
+				apiErr.Message = ErrBadgeAlreadyIssued
+			}
+			apiErr.kind = ErrAlreadyIssued
+			return i, &alreadyIssuedError{apiErr}
+		}
+		return i, apiErr
+	}
+
+	return decodeResponse[BadgeInfo]("credly.IssueBadge", resp, http.StatusCreated)
+}
+
+// idempotencyKeyHeader is the request header carrying IssueOptions.IdempotencyKey.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyKeyFor returns an idempotency key identifying the issuance of a
+// badge template to a recipient, for IssueOptions.IdempotencyKey. The email is
+// compared ignoring case and surrounding whitespace.
+func IdempotencyKeyFor(templateId, email string) string {
+	return deriveKey(templateId, strings.ToLower(strings.TrimSpace(email)))
+}
+
+// deriveKey returns a hex-encoded hash of parts, used to build idempotency keys.
+func deriveKey(parts ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(h[:])
+}
+
+// dryRunBadgeId is the ID of the synthetic badges returned in dry-run mode.
+const dryRunBadgeId = "dry-run"
+
+// dryRunBadge returns the synthetic badge standing for a badge issued in dry-run mode.
+func dryRunBadge(templateId, email, firstName, lastName string, issuedAt time.Time, opts IssueOptions) BadgeInfo {
+	b := BadgeInfo{
+		Id:                  dryRunBadgeId,
+		IssuedAt:            issuedAt,
+		ExpiresAt:           opts.ExpiresAt,
+		State:               BadgeStatePending,
+		CustomFields:        opts.CustomFields,
+		RecipientAttributes: opts.RecipientAttributes,
+		DryRun:              true,
 	}
+	b.Template.Id = templateId
+	b.User.Id = strings.TrimSpace(opts.UserId)
+	b.User.Email = email
+	b.User.FirstName = firstName
+	b.User.LastName = lastName
 
-	if resp.StatusCode != http.StatusCreated {
-		return i, fmt.Errorf("[credly.IssueBadge] API request failed with status code: %d", resp.StatusCode)
+	return b
+}
+
+// normalizeRecipient trims the recipient details and checks that the email is
+// well-formed and the names are not empty.
+func normalizeRecipient(op, email, firstName, lastName string) (string, string, string, error) {
+	email = strings.TrimSpace(email)
+
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return email, strings.TrimSpace(firstName), strings.TrimSpace(lastName), fmt.Errorf("[%s] %w: %q", op, ErrInvalidEmail, email)
 	}
 
-	var badgeResp issueBadgeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&badgeResp); err != nil {
-		return i, fmt.Errorf("[credly.IssueBadge] Failed to parse JSON data: %v", err)
+	firstName, lastName, err := normalizeNames(op, firstName, lastName)
+	return email, firstName, lastName, err
+}
+
+// normalizeNames trims the recipient names and checks that they are not empty.
+func normalizeNames(op, firstName, lastName string) (string, string, error) {
+	firstName = strings.TrimSpace(firstName)
+	lastName = strings.TrimSpace(lastName)
+
+	if firstName == "" || lastName == "" {
+		return firstName, lastName, fmt.Errorf("[%s] First and last names must not be empty", op)
 	}
 
-	return badgeResp.Data, nil
+	return firstName, lastName, nil
 }
 
 // GetBadges retrieves all badges for a given email, optionally filtered by collections.
+// All pages of results are fetched, in Credly's default order: to sort them, e.g.
+// most recent first, use GetBadgesByFilter with BadgeFilter.Sort.
+//
+// With lenient decoding enabled (see WithLenientDecoding), malformed badges are
+// skipped and reported in a *PartialDecodeError returned alongside the valid ones.
 //
 // email: The recipient's email address.
-// collections: A list of collection tags to filter badges.
+// collections: A list of collection tags to filter badges. These are the reporting tags
+// set on badge templates by the organization, not the collections recipients create
+// on their Credly profile.
 // Returns: A slice of BadgeInfo representing the retrieved badges, or an error if the operation fails.
 func (c *Client) GetBadges(email string, collections []string) (b []BadgeInfo, err error) {
-	qUrl := fmt.Sprintf("https://api.credly.com/v1/organizations/%s/badges", c.OrganizationId)
-	qUrl = fmt.Sprintf("%s?filter=recipient_email_all::%s", qUrl, url.QueryEscape(email))
+	if err := checkNotEmpty("credly.GetBadges", "Email", email); err != nil {
+		return nil, err
+	}
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{Email: email, Tags: collections})
+	if err != nil {
+		return nil, err
+	}
+
+	return listAll[BadgeInfo](context.Background(), c, "credly.GetBadges", qUrl)
+}
+
+// GetBadgesPage retrieves a single page of badges for a given email, optionally filtered by collections.
+//
+// email: The recipient's email address.
+// collections: A list of collection tags to filter badges, see GetBadges.
+// page: The 1-based index of the page to retrieve.
+// Returns: The badges of the page and the pagination metadata, or an error if the operation fails.
+func (c *Client) GetBadgesPage(email string, collections []string, page int) ([]BadgeInfo, PageMetadata, error) {
+	if err := checkNotEmpty("credly.GetBadgesPage", "Email", email); err != nil {
+		return nil, PageMetadata{}, err
+	}
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{Email: email, Tags: collections})
+	if err != nil {
+		return nil, PageMetadata{}, err
+	}
+	qUrl = withQueryParam(qUrl, "page", strconv.Itoa(page))
+
+	return fetchPage[BadgeInfo](context.Background(), c, "credly.GetBadgesPage", qUrl)
+}
+
+// GetBadgesByState retrieves all badges for a given email in the given state,
+// e.g. BadgeStateAccepted. All pages of results are fetched.
+//
+// email: The recipient's email address.
+// state: One of the BadgeState constants.
+// Returns: A slice of BadgeInfo representing the retrieved badges, or an error if the state is unknown or the operation fails.
+func (c *Client) GetBadgesByState(email, state string) ([]BadgeInfo, error) {
+	if !badgeStates[state] {
+		return nil, fmt.Errorf("[credly.GetBadgesByState] Unknown badge state: %q", state)
+	}
+	if err := checkNotEmpty("credly.GetBadgesByState", "Email", email); err != nil {
+		return nil, err
+	}
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{Email: email, State: state})
+	if err != nil {
+		return nil, err
+	}
+
+	return listAll[BadgeInfo](context.Background(), c, "credly.GetBadgesByState", qUrl)
+}
+
+// GetBadge retrieves a specific badge for a given email and badge ID.
+//
+// Despite its name, badgeId is matched against the badge template ID, so this
+// returns the first of the recipient's badges issued from that template. Use
+// GetBadgeById to retrieve an issued badge by its own ID.
+//
+// email: The recipient's email address.
+// badgeId: The ID of the badge template.
+// Returns: A BadgeInfo representing the retrieved badge, ErrNotFound if the recipient has no such badge,
+// or another error if the operation fails.
+func (c *Client) GetBadge(email, badgeId string) (b BadgeInfo, err error) {
+	return c.GetBadgeContext(context.Background(), email, badgeId)
+}
 
-	if len(collections) > 0 {
-		colFilter := fmt.Sprintf("|badge_templates[reporting_tags]::%s", strings.Join(collections, ","))
-		qUrl = fmt.Sprintf("%s%s", qUrl, url.QueryEscape(colFilter))
+// GetBadgeContext is like GetBadge but uses ctx for the request.
+func (c *Client) GetBadgeContext(ctx context.Context, email, badgeId string) (b BadgeInfo, err error) {
+	if err := checkNotEmpty("credly.GetBadge", "Email", email); err != nil {
+		return b, err
+	}
+	if err := checkNotEmpty("credly.GetBadge", "Template ID", badgeId); err != nil {
+		return b, err
 	}
 
-	req, err := http.NewRequest("GET", qUrl, nil)
+	url, err := c.filteredBadgesUrl(BadgeFilter{Email: email, TemplateId: badgeId})
+	if err != nil {
+		return b, err
+	}
+
+	items, _, err := fetchPage[BadgeInfo](ctx, c, "credly.GetBadge", url)
+	if err != nil {
+		return b, err
+	}
+
+	if len(items) == 0 {
+		return b, fmt.Errorf("[credly.GetBadge] %w: no badge from template %s for %s", ErrNotFound, badgeId, email)
+	}
+
+	return items[0], nil
+}
+
+// GetBadgeById retrieves a single issued badge by its ID.
+//
+// badgeId: The ID of the issued badge.
+// Returns: The BadgeInfo, ErrNotFound if Credly responds with 404, or another error if the operation fails.
+func (c *Client) GetBadgeById(badgeId string) (BadgeInfo, error) {
+	return c.GetBadgeByIdContext(context.Background(), badgeId)
+}
+
+// GetBadgeByIdContext is like GetBadgeById but uses ctx for the request.
+func (c *Client) GetBadgeByIdContext(ctx context.Context, badgeId string) (BadgeInfo, error) {
+	return c.getBadgeById(ctx, badgeId)
+}
+
+// getBadgeById retrieves a single badge using the direct badge endpoint.
+func (c *Client) getBadgeById(ctx context.Context, badgeId string) (b BadgeInfo, err error) {
+	if err := checkNotEmpty("credly.GetBadgeById", "Badge ID", badgeId); err != nil {
+		return b, err
+	}
+
+	qUrl := fmt.Sprintf("%s/organizations/%s/badges/%s", c.baseURL(), c.OrganizationId, url.PathEscape(badgeId))
+
+	req, err := http.NewRequestWithContext(withOperation(ctx, "credly.GetBadgeById"), "GET", qUrl, nil)
 	if err != nil {
 		return b, err
 	}
@@ -137,28 +679,271 @@ func (c *Client) GetBadges(email string, collections []string) (b []BadgeInfo, e
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return b, fmt.Errorf("[credly.GetBadges] API request failed with status code: %d", resp.StatusCode)
+	return decodeResponse[BadgeInfo]("credly.GetBadgeById", resp, http.StatusOK)
+}
+
+// updatableBadgeFields lists the fields of an issued badge that can be changed with UpdateBadge.
+var updatableBadgeFields = map[string]bool{
+	"issued_to_first_name":  true,
+	"issued_to_middle_name": true,
+	"issued_to_last_name":   true,
+	"expires_at":            true,
+}
+
+// UpdateBadge updates the details of an issued badge, e.g. to fix a typo in the
+// recipient's name. The badge keeps its ID and URL.
+//
+// badgeId: The ID of the issued badge.
+// fields: The fields to update and their new values. Only issued_to_first_name,
+// issued_to_middle_name, issued_to_last_name and expires_at are accepted.
+// Returns: The updated BadgeInfo, or an error if the operation fails.
+func (c *Client) UpdateBadge(badgeId string, fields map[string]interface{}) (b BadgeInfo, err error) {
+	if len(fields) == 0 {
+		return b, fmt.Errorf("[credly.UpdateBadge] No fields to update")
 	}
 
-	var badgesResp getBadgesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&badgesResp); err != nil {
-		return b, fmt.Errorf("[credly.GetBadges] Failed to parse JSON data: %v", err)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !updatableBadgeFields[k] {
+			return b, fmt.Errorf("[credly.UpdateBadge] Unknown or read-only field: %q", k)
+		}
 	}
 
-	return badgesResp.Data, nil
+	url := fmt.Sprintf("%s/organizations/%s/badges/%s", c.baseURL(), c.OrganizationId, badgeId)
+
+	return c.putBadge(context.Background(), "credly.UpdateBadge", url, fields)
 }
 
-// GetBadge retrieves a specific badge for a given email and badge ID.
+// RevokeBadge revokes an issued badge. The recipient can no longer use it, and
+// verifiers see it as revoked.
+//
+// badgeId: The ID of the issued badge.
+// reason: The reason for the revocation, shown to the recipient.
+// Returns: The revoked BadgeInfo, or an error if the operation fails.
+func (c *Client) RevokeBadge(badgeId, reason string) (BadgeInfo, error) {
+	return c.revokeBadge(context.Background(), badgeId, reason)
+}
+
+// revokeBadge revokes an issued badge.
+func (c *Client) revokeBadge(ctx context.Context, badgeId, reason string) (BadgeInfo, error) {
+	url := fmt.Sprintf("%s/organizations/%s/badges/%s/revoke", c.baseURL(), c.OrganizationId, badgeId)
+
+	return c.putBadge(ctx, "credly.RevokeBadge", url, map[string]interface{}{"reason": reason})
+}
+
+// AcceptBadge accepts a pending badge on behalf of its recipient, so that it
+// appears on their Credly profile. Accepting a badge that is already accepted
+// succeeds and returns it unchanged.
+//
+// badgeId: The ID of the issued badge.
+// Returns: The accepted BadgeInfo, or an error if the operation fails.
+func (c *Client) AcceptBadge(badgeId string) (BadgeInfo, error) {
+	return c.setBadgeState(context.Background(), "credly.AcceptBadge", badgeId, "accept", BadgeStateAccepted)
+}
+
+// RejectBadge rejects a pending badge on behalf of its recipient. Rejecting a
+// badge that is already rejected succeeds and returns it unchanged.
+//
+// badgeId: The ID of the issued badge.
+// Returns: The rejected BadgeInfo, or an error if the operation fails.
+func (c *Client) RejectBadge(badgeId string) (BadgeInfo, error) {
+	return c.setBadgeState(context.Background(), "credly.RejectBadge", badgeId, "reject", BadgeStateRejected)
+}
+
+// ResendBadgeNotification sends the badge notification email to the recipient
+// of an issued badge again, e.g. when they missed the original one.
+//
+// Credly only notifies recipients of pending or accepted badges. For a revoked,
+// rejected or expired badge, the returned error matches ErrValidation.
+//
+// badgeId: The ID of the issued badge.
+// Returns: An error if the operation fails.
+func (c *Client) ResendBadgeNotification(badgeId string) error {
+	url := fmt.Sprintf("%s/organizations/%s/badges/%s/resend", c.baseURL(), c.OrganizationId, badgeId)
+
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "credly.ResendBadgeNotification"), "POST", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError("credly.ResendBadgeNotification", resp)
+	}
+
+	return nil
+}
+
+// DeleteBadge permanently deletes an issued badge, e.g. a test badge or one
+// issued in error. Unlike a revoked badge, a deleted badge no longer appears
+// anywhere.
+//
+// Credly only deletes badges shortly after they are issued and before they
+// are accepted. If it refuses, the returned error matches ErrBadgeNotDeletable:
+// RevokeBadge can be used instead.
+//
+// badgeId: The ID of the issued badge.
+// Returns: An error if the operation fails.
+func (c *Client) DeleteBadge(badgeId string) error {
+	url := fmt.Sprintf("%s/organizations/%s/badges/%s", c.baseURL(), c.OrganizationId, badgeId)
+
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "credly.DeleteBadge"), "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		apiErr := newAPIError("credly.DeleteBadge", resp)
+		if apiErr.Message == "" {
+			apiErr.Message = ErrBadgeNotDeletable.Error()
+		}
+		apiErr.kind = ErrBadgeNotDeletable
+		return apiErr
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError("credly.DeleteBadge", resp)
+	}
+
+	return nil
+}
+
+// setBadgeState sends action for a badge, expected to move it to state. If
+// Credly refuses the action because the badge is already in state, the badge
+// is returned without error.
+func (c *Client) setBadgeState(ctx context.Context, op, badgeId, action, state string) (BadgeInfo, error) {
+	url := fmt.Sprintf("%s/organizations/%s/badges/%s/%s", c.baseURL(), c.OrganizationId, badgeId, action)
+
+	b, err := c.putBadge(ctx, op, url, map[string]interface{}{})
+	if errors.Is(err, ErrValidation) {
+		if current, getErr := c.getBadgeById(ctx, badgeId); getErr == nil && current.State == state {
+			return current, nil
+		}
+	}
+
+	return b, err
+}
+
+// GetBadgesForEmails retrieves all badges of several recipients.
+// See GetBadgesForEmailsContext.
+func (c *Client) GetBadgesForEmails(emails []string, concurrency int) (map[string][]BadgeInfo, error) {
+	return c.GetBadgesForEmailsContext(context.Background(), emails, concurrency)
+}
+
+// GetBadgesForEmailsContext retrieves all badges of several recipients, e.g. to
+// reconcile a roster against Credly, with up to concurrency lookups in flight at
+// once. A failed lookup doesn't prevent the others.
+//
+// ctx: The context controlling the requests. Cancelling it aborts pending lookups.
+// emails: The recipients' email addresses. Duplicates are looked up once.
+// concurrency: The maximum number of lookups in flight. Zero or less uses a default.
+// Returns: A map of email to the recipient's badges for the lookups that succeeded,
+// and a *LookupError holding the errors of those that failed, if any.
+func (c *Client) GetBadgesForEmailsContext(ctx context.Context, emails []string, concurrency int) (map[string][]BadgeInfo, error) {
+	if concurrency <= 0 {
+		concurrency = maxConcurrentRequests
+	}
+
+	unique := make([]string, 0, len(emails))
+	seen := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		if !seen[email] {
+			seen[email] = true
+			unique = append(unique, email)
+		}
+	}
+
+	badges := make(map[string][]BadgeInfo, len(unique))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	forEach(ctx, len(unique), concurrency, func(ctx context.Context, i int) {
+		var b []BadgeInfo
+		var qUrl string
+		err := checkNotEmpty("credly.GetBadgesForEmails", "Email", unique[i])
+		if err == nil {
+			qUrl, err = c.filteredBadgesUrl(BadgeFilter{Email: unique[i]})
+		}
+		if err == nil {
+			b, err = listAll[BadgeInfo](ctx, c, "credly.GetBadgesForEmails", qUrl)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[unique[i]] = err
+			return
+		}
+		badges[unique[i]] = b
+	})
+
+	if len(errs) > 0 {
+		return badges, &LookupError{Op: "credly.GetBadgesForEmails", Errors: errs}
+	}
+
+	return badges, nil
+}
+
+// ReplaceBadge moves a recipient's badge to another template, e.g. after the
+// template was superseded, by revoking the old badge and issuing a new one.
+//
+// The two steps aren't atomic: if issuing the new badge fails after the old
+// one was revoked, a *ReplaceBadgeError is returned so the recipient isn't
+// silently left without a badge. The recipient is validated before revoking.
+// In dry-run mode, the old badge isn't revoked.
 //
+// oldBadgeId: The ID of the issued badge to revoke.
+// newTemplateId: The ID of the badge template to issue.
 // email: The recipient's email address.
-// badgeId: The ID of the badge to be retrieved.
-// Returns: A BadgeInfo representing the retrieved badge, or an error if the operation fails.
-func (c *Client) GetBadge(email, badgeId string) (b BadgeInfo, err error) {
-	url := fmt.Sprintf("https://api.credly.com/v1/organizations/%s/badges", c.OrganizationId)
-	url = fmt.Sprintf("%s?filter=recipient_email_all::%s|badge_template_id::%s", url, email, badgeId)
+// firstName: The recipient's first name.
+// lastName: The recipient's last name.
+// Returns: The new BadgeInfo, or an error if the operation fails.
+func (c *Client) ReplaceBadge(oldBadgeId, newTemplateId, email, firstName, lastName string) (BadgeInfo, error) {
+	ctx := context.Background()
+
+	if _, _, _, err := normalizeRecipient("credly.ReplaceBadge", email, firstName, lastName); err != nil {
+		return BadgeInfo{}, err
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	if !c.dryRun {
+		if _, err := c.revokeBadge(ctx, oldBadgeId, replacedReason); err != nil {
+			return BadgeInfo{}, err
+		}
+	}
+
+	b, err := c.issueBadge(ctx, newTemplateId, email, firstName, lastName, IssueOptions{})
+	if err != nil {
+		return b, &ReplaceBadgeError{OldBadgeId: oldBadgeId, Err: err}
+	}
+
+	return b, nil
+}
+
+// replacedReason is the revocation reason of badges replaced by ReplaceBadge.
+const replacedReason = "Replaced by a new badge"
+
+// putBadge sends params with a PUT request to url and decodes the badge in the response.
+func (c *Client) putBadge(ctx context.Context, op, url string, params map[string]interface{}) (b BadgeInfo, err error) {
+	reqBody, err := json.Marshal(params)
+	if err != nil {
+		return b, fmt.Errorf("[%s] Failed to marshal parameters: %v", op, err)
+	}
+
+	req, err := http.NewRequestWithContext(withOperation(ctx, op), "PUT", url, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return b, err
 	}
@@ -169,14 +954,243 @@ func (c *Client) GetBadge(email, badgeId string) (b BadgeInfo, err error) {
 	}
 	defer resp.Body.Close()
 
-	var badgesResp getBadgesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&badgesResp); err != nil {
-		return b, fmt.Errorf("Failed to parse JSON data: %v", err)
+	return decodeResponse[BadgeInfo](op, resp, http.StatusOK)
+}
+
+// GetBadgesByIds retrieves the current state of several badges by their IDs.
+// See GetBadgesByIdsContext.
+func (c *Client) GetBadgesByIds(ids []string) (map[string]BadgeInfo, map[string]error) {
+	return c.GetBadgesByIdsContext(context.Background(), ids)
+}
+
+// GetBadgesByIdsContext retrieves the current state of several badges by their IDs.
+// Badges are fetched individually from the direct badge endpoint, with a bounded
+// number of requests in flight at once.
+//
+// ctx: The context controlling the requests. Cancelling it aborts pending fetches.
+// ids: The IDs of the issued badges to retrieve. Duplicates are fetched once.
+// Returns: A map of badge ID to BadgeInfo for the badges found, and a map of badge ID
+// to error for the ones that could not be retrieved (ErrNotFound for unknown badges).
+func (c *Client) GetBadgesByIdsContext(ctx context.Context, ids []string) (map[string]BadgeInfo, map[string]error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	badges := make(map[string]BadgeInfo, len(unique))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	forEach(ctx, len(unique), maxConcurrentRequests, func(ctx context.Context, i int) {
+		b, err := c.getBadgeById(ctx, unique[i])
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[unique[i]] = err
+			return
+		}
+		badges[unique[i]] = b
+	})
+
+	return badges, errs
+}
+
+// EnsureBadge makes sure a recipient holds a badge for the given template,
+// issuing it only if they don't have it yet.
+//
+// If issuance races with another issuance for the same recipient and Credly
+// reports the badge as already issued, the existing badge is fetched and returned.
+//
+// templateId: The ID of the badge template.
+// email: The recipient's email address.
+// firstName: The recipient's first name.
+// lastName: The recipient's last name.
+// Returns: The recipient's badge, whether it was newly created, or an error if the operation fails.
+func (c *Client) EnsureBadge(templateId, email, firstName, lastName string) (b BadgeInfo, created bool, err error) {
+	// Look up and issue the badge for the same recipient, whatever the input formatting
+	email, firstName, lastName, err = normalizeRecipient("credly.EnsureBadge", email, firstName, lastName)
+	if err != nil {
+		return b, false, err
+	}
+
+	b, err = c.GetBadge(email, templateId)
+	if err == nil {
+		return b, false, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return b, false, err
+	}
+
+	b, err = c.IssueBadge(templateId, email, firstName, lastName)
+	if err == nil {
+		return b, true, nil
+	}
+	if !isBadgeAlreadyIssued(err) {
+		return b, false, err
+	}
+
+	// Issued concurrently since the pre-check, fetch the existing badge
+	b, err = c.GetBadge(email, templateId)
+	if errors.Is(err, ErrNotFound) {
+		return b, false, fmt.Errorf("[credly.EnsureBadge] Badge reported as already issued but could not be found")
+	}
+
+	return b, false, err
+}
+
+// IssueOrGetBadge issues a badge to a recipient, or returns the badge they
+// already hold for the template if Credly reports it as already issued.
+//
+// Unlike EnsureBadge, which looks the badge up before issuing it, the badge is
+// issued first: this saves a request when most recipients don't hold the badge
+// yet, e.g. when issuing badges as exams are passed.
+//
+// templateId: The ID of the badge template.
+// email: The recipient's email address.
+// firstName: The recipient's first name.
+// lastName: The recipient's last name.
+// Returns: The recipient's badge, whether it was newly issued, or an error if the operation fails.
+func (c *Client) IssueOrGetBadge(templateId, email, firstName, lastName string) (b BadgeInfo, created bool, err error) {
+	b, err = c.IssueBadge(templateId, email, firstName, lastName)
+	if err == nil {
+		return b, true, nil
+	}
+	if !isBadgeAlreadyIssued(err) {
+		return b, false, err
 	}
 
-	if len(badgesResp.Data) == 0 {
-		return b, nil
+	b, err = c.GetBadge(strings.TrimSpace(email), templateId)
+	if errors.Is(err, ErrNotFound) {
+		return b, false, fmt.Errorf("[credly.IssueOrGetBadge] Badge reported as already issued but could not be found")
 	}
 
-	return badgesResp.Data[0], nil
+	return b, false, err
+}
+
+// GetActiveBadges retrieves the badges a recipient can currently use.
+// See GetActiveBadgesContext.
+func (c *Client) GetActiveBadges(email string) ([]BadgeInfo, error) {
+	return c.GetActiveBadgesContext(context.Background(), email)
+}
+
+// GetActiveBadgesContext retrieves the badges a recipient can currently use,
+// i.e. accepted badges that are not expired (see BadgeInfo.IsActive).
+// Accepted badges are filtered by Credly, expiration is checked client-side.
+//
+// ctx: The context controlling the requests.
+// email: The recipient's email address.
+// Returns: A slice of BadgeInfo representing the active badges, or an error if the operation fails.
+func (c *Client) GetActiveBadgesContext(ctx context.Context, email string) ([]BadgeInfo, error) {
+	if err := checkNotEmpty("credly.GetActiveBadges", "Email", email); err != nil {
+		return nil, err
+	}
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{Email: email, State: BadgeStateAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	badges, err := listAll[BadgeInfo](ctx, c, "credly.GetActiveBadges", qUrl)
+	if err != nil {
+		return badges, err
+	}
+
+	now := time.Now()
+	active := make([]BadgeInfo, 0, len(badges))
+	for _, b := range badges {
+		if b.IsActive(now) {
+			active = append(active, b)
+		}
+	}
+
+	return active, nil
+}
+
+// GetBadgesByTemplate retrieves every badge issued from a badge template.
+// See GetBadgesByTemplateContext.
+func (c *Client) GetBadgesByTemplate(templateId string) ([]BadgeInfo, error) {
+	return c.GetBadgesByTemplateContext(context.Background(), templateId)
+}
+
+// GetBadgesByTemplateContext retrieves every badge issued from a badge template,
+// e.g. to audit the holders of a certification. All pages of results are fetched.
+//
+// ctx: The context controlling the requests.
+// templateId: The ID of the badge template.
+// Returns: A slice of BadgeInfo representing the issued badges, or an error if the operation fails.
+func (c *Client) GetBadgesByTemplateContext(ctx context.Context, templateId string) ([]BadgeInfo, error) {
+	if err := checkNotEmpty("credly.GetBadgesByTemplate", "Template ID", templateId); err != nil {
+		return nil, err
+	}
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{TemplateId: templateId})
+	if err != nil {
+		return nil, err
+	}
+
+	return listAll[BadgeInfo](ctx, c, "credly.GetBadgesByTemplate", qUrl)
+}
+
+// GetBadgesByCollection retrieves every badge of the organization in a collection.
+// See GetBadgesByCollectionContext.
+func (c *Client) GetBadgesByCollection(collection string) ([]BadgeInfo, error) {
+	return c.GetBadgesByCollectionContext(context.Background(), collection)
+}
+
+// GetBadgesByCollectionContext retrieves every badge of the organization whose
+// template belongs to a collection, whoever the recipient, e.g. to list all the
+// holders of a certification track. All pages of results are fetched.
+//
+// Like in GetBadges, collections are the reporting tags set on badge templates
+// by the organization.
+//
+// ctx: The context controlling the requests.
+// collection: The reporting tag of the collection. It must not be empty.
+// Returns: A slice of BadgeInfo representing the badges in the collection, or an error if the operation fails.
+func (c *Client) GetBadgesByCollectionContext(ctx context.Context, collection string) ([]BadgeInfo, error) {
+	if collection == "" {
+		return nil, fmt.Errorf("[credly.GetBadgesByCollection] Collection must not be empty")
+	}
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{Tags: []string{collection}})
+	if err != nil {
+		return nil, err
+	}
+
+	return listAll[BadgeInfo](ctx, c, "credly.GetBadgesByCollection", qUrl)
+}
+
+// SearchBadges retrieves the organization's badges whose recipient matches a
+// free-text query, e.g. when only part of an email or a name is known.
+// See SearchBadgesContext.
+func (c *Client) SearchBadges(query string) ([]BadgeInfo, error) {
+	return c.SearchBadgesContext(context.Background(), query)
+}
+
+// SearchBadgesContext retrieves the organization's badges whose recipient
+// matches a free-text query, across all templates, most recently issued first.
+// All pages of results are fetched.
+//
+// Matching is done by Credly's query parameter: the query is matched against
+// the recipient's first name, last name and email, case-insensitively, and
+// partial matches are returned (e.g. "john" matches "John Doe" and
+// "johnny@example.com"). Use GetBadges to look up the badges of an exact email.
+//
+// ctx: The context controlling the requests.
+// query: The text to search for. It must not be empty.
+// Returns: A slice of BadgeInfo representing the matching badges, or an error if the operation fails.
+func (c *Client) SearchBadgesContext(ctx context.Context, query string) ([]BadgeInfo, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("[credly.SearchBadges] Search query must not be empty")
+	}
+
+	qUrl := fmt.Sprintf("%s/organizations/%s/badges?query=%s&sort=-issued_at", c.baseURL(), c.OrganizationId, url.QueryEscape(query))
+
+	return listAll[BadgeInfo](ctx, c, "credly.SearchBadges", qUrl)
 }
@@ -15,39 +15,230 @@
 package credly
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
 )
 
-// getBadgeTemplateResponse represents the response structure when fetching a specific badge template.
-type getBadgeTemplateResponse struct {
-	Data BadgeTemplate `json:"data"`
+// BadgeTemplate represents the details of a badge template in Credly.
+type BadgeTemplate struct {
+	Id          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Skills lists the skill names attached to the template, as entered by the
+	// organization. The organization API has no skill library lookup, so they
+	// are not matched against Credly's canonical skills by the client.
+	Skills []string `json:"skills"`
+
+	// SkillDetails lists the skills attached to the template with their
+	// metadata, in the same order as Skills. Credly returns either skill
+	// objects or plain names: in the latter case, only Skill.Name is set.
+	SkillDetails []Skill `json:"-"`
+
+	// ReportingTags are the collections the template belongs to, used to
+	// filter badges in GetBadges.
+	ReportingTags []string `json:"reporting_tags"`
+
+	Url        string `json:"url"`
+	ImageUrl   string `json:"image_url"`
+	VanitySlug string `json:"vanity_slug"`
+
+	// State is the publication state of the template, one of the
+	// BadgeTemplateState constants.
+	State string `json:"state"`
+
+	// Level, TypeCategory, TimeToEarn and Cost are the attributes shown on the
+	// template page, e.g. "Intermediate", "Certification", "Weeks" and "Paid".
+	// They are empty when not set by the organization.
+	Level        string `json:"level"`
+	TypeCategory string `json:"type_category"`
+	TimeToEarn   string `json:"time_to_earn"`
+	Cost         string `json:"cost"`
 }
 
-// getBadgeTemplatesResponse represents the response structure when fetching multiple badge templates.
-type getBadgeTemplatesResponse struct {
-	Data []BadgeTemplate `json:"data"`
+// Skill is a skill attached to a badge template, see BadgeTemplate.SkillDetails.
+type Skill struct {
+	// Id is the ID of the skill in Credly's skill library.
+	Id string `json:"id"`
+
+	Name      string `json:"name"`
+	VanityUrl string `json:"vanity_url"`
 }
 
-// BadgeTemplate represents the details of a badge template in Credly.
-type BadgeTemplate struct {
-	Id         string   `json:"id,omitempty"`
-	Name       string   `json:"name"`
-	Skills     []string `json:"skills"`
-	Url        string   `json:"url"`
-	ImageUrl   string   `json:"image_url"`
-	VanitySlug string   `json:"vanity_slug"`
+// UnmarshalJSON implements the json.Unmarshaler interface. Skills are accepted
+// both as objects and as plain names.
+func (s *Skill) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*s = Skill{Name: name}
+		return nil
+	}
+
+	type skill Skill
+	return json.Unmarshal(data, (*skill)(s))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, filling both
+// Skills and SkillDetails from the skills Credly returns.
+func (t *BadgeTemplate) UnmarshalJSON(data []byte) error {
+	type badgeTemplate BadgeTemplate
+	var aux struct {
+		badgeTemplate
+		Skills []Skill `json:"skills"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*t = BadgeTemplate(aux.badgeTemplate)
+	if aux.Skills != nil {
+		t.SkillDetails = aux.Skills
+		t.Skills = make([]string, len(aux.Skills))
+		for i, skill := range aux.Skills {
+			t.Skills[i] = skill.Name
+		}
+	}
+	return nil
+}
+
+// BadgeTemplateState values, see BadgeTemplate.State.
+const (
+	// BadgeTemplateStateActive templates can be issued.
+	BadgeTemplateStateActive = "active"
+	// BadgeTemplateStateArchived templates are no longer issued, but badges
+	// issued from them remain valid.
+	BadgeTemplateStateArchived = "archived"
+	// BadgeTemplateStateDraft templates haven't been published yet.
+	BadgeTemplateStateDraft = "draft"
+)
+
+// badgeTemplateStates is the set of known BadgeTemplateState values.
+var badgeTemplateStates = map[string]bool{
+	BadgeTemplateStateActive:   true,
+	BadgeTemplateStateArchived: true,
+	BadgeTemplateStateDraft:    true,
+}
+
+// BadgeTemplateFilter selects the organization's badge templates to list, see
+// GetBadgeTemplatesByFilter. Empty fields don't filter, and set fields must all match.
+type BadgeTemplateFilter struct {
+	// State matches the state of the template, one of the BadgeTemplateState constants.
+	State string
+
+	// VanitySlug matches the vanity slug of the template.
+	VanitySlug string
+
+	// Sort orders the templates by one of name, created_at or updated_at,
+	// descending if prefixed with "-". Empty uses Credly's default order.
+	Sort string
+}
+
+// badgeTemplateSortKeys is the set of keys accepted in BadgeTemplateFilter.Sort.
+var badgeTemplateSortKeys = map[string]bool{
+	"name":       true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// Encode returns the filter in Credly's filter query language, e.g.
+// "state::active", to be sent in the filter query parameter, or "" if no
+// field is set. Values containing "|" can't be expressed and are rejected.
+func (f BadgeTemplateFilter) Encode() (string, error) {
+	var terms []string
+	if f.State != "" {
+		if !badgeTemplateStates[f.State] {
+			return "", fmt.Errorf("[credly.BadgeTemplateFilter] Unknown badge template state: %q", f.State)
+		}
+		terms = append(terms, "state::"+f.State)
+	}
+	if f.VanitySlug != "" {
+		if strings.Contains(f.VanitySlug, "|") {
+			return "", fmt.Errorf("[credly.BadgeTemplateFilter] Invalid vanity_slug, must not contain \"|\": %q", f.VanitySlug)
+		}
+		terms = append(terms, "vanity_slug::"+f.VanitySlug)
+	}
+
+	return strings.Join(terms, "|"), nil
 }
 
+// filteredBadgeTemplatesUrl builds the URL listing the organization's badge templates matching filter.
+func (c *Client) filteredBadgeTemplatesUrl(filter BadgeTemplateFilter) (string, error) {
+	qUrl := fmt.Sprintf("%s/organizations/%s/badge_templates", c.baseURL(), c.OrganizationId)
+
+	f, err := filter.Encode()
+	if err != nil {
+		return "", err
+	}
+	if f != "" {
+		qUrl = withQueryParam(qUrl, "filter", f)
+	}
+	if filter.Sort != "" {
+		if err := checkSort("credly.BadgeTemplateFilter", filter.Sort, badgeTemplateSortKeys); err != nil {
+			return "", err
+		}
+		qUrl = withQueryParam(qUrl, "sort", filter.Sort)
+	}
+
+	return qUrl, nil
+}
+
+// GetBadgeTemplatesByFilter retrieves the organization's badge templates
+// matching filter, e.g. only the active ones. All pages of results are fetched.
+//
+// With lenient decoding enabled (see WithLenientDecoding), malformed templates are
+// skipped and reported in a *PartialDecodeError returned alongside the valid ones.
+//
+// filter: The criteria the templates must match.
+// Returns: A slice of BadgeTemplate, or an error if the filter is invalid or the operation fails.
+func (c *Client) GetBadgeTemplatesByFilter(filter BadgeTemplateFilter) ([]BadgeTemplate, error) {
+	qUrl, err := c.filteredBadgeTemplatesUrl(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return listAll[BadgeTemplate](context.Background(), c, "credly.GetBadgeTemplatesByFilter", qUrl)
+}
+
+// GetActiveBadgeTemplates retrieves the organization's badge templates that can
+// be issued, leaving out archived and draft ones. See GetBadgeTemplatesByFilter.
+func (c *Client) GetActiveBadgeTemplates() ([]BadgeTemplate, error) {
+	return c.GetBadgeTemplatesByFilter(BadgeTemplateFilter{State: BadgeTemplateStateActive})
+}
+
+// BadgeTemplateSummary is a lightweight view of a badge template, see GetBadgeTemplateSummaries.
+type BadgeTemplateSummary struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	ImageUrl string `json:"image_url"`
+}
+
+// badgeTemplateSummaryFields lists the template fields requested by GetBadgeTemplateSummaries.
+var badgeTemplateSummaryFields = []string{"id", "name", "image_url"}
+
 // GetBadgeTemplate retrieves a specific badge template by its ID.
 //
 // templateId: The ID of the badge template to be retrieved.
 // Returns: A BadgeTemplate representing the retrieved template, or an error if the operation fails.
 func (c *Client) GetBadgeTemplate(templateId string) (b BadgeTemplate, err error) {
-	url := fmt.Sprintf("https://api.credly.com/v1/organizations/%s/badge_templates/%s", c.OrganizationId, templateId)
+	return c.getBadgeTemplate(context.Background(), "credly.GetBadgeTemplate", templateId)
+}
+
+// getBadgeTemplate retrieves a specific badge template by its ID.
+func (c *Client) getBadgeTemplate(ctx context.Context, op, templateId string) (b BadgeTemplate, err error) {
+	if err := checkNotEmpty(op, "Template ID", templateId); err != nil {
+		return b, err
+	}
+
+	qUrl := fmt.Sprintf("%s/organizations/%s/badge_templates/%s", c.baseURL(), c.OrganizationId, url.PathEscape(templateId))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(withOperation(ctx, op), "GET", qUrl, nil)
 	if err != nil {
 		return b, err
 	}
@@ -58,25 +249,108 @@ func (c *Client) GetBadgeTemplate(templateId string) (b BadgeTemplate, err error
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return b, fmt.Errorf("[credly.GetBadgeTemplate] API request failed with status code: %d", resp.StatusCode)
+	return decodeResponse[BadgeTemplate](op, resp, http.StatusOK)
+}
+
+// GetBadgeTemplatesByIds retrieves several badge templates by their IDs, e.g.
+// the templates referenced in a configuration. Templates are fetched
+// individually, with a bounded number of requests in flight at once.
+//
+// ids: The IDs of the badge templates. Duplicates are fetched once.
+// Returns: The templates retrieved, in the order of ids, and a *LookupError
+// keyed by template ID for those that could not be retrieved, if any (matching
+// ErrNotFound for unknown templates), or an error if an ID is empty.
+func (c *Client) GetBadgeTemplatesByIds(ids []string) ([]BadgeTemplate, error) {
+	ctx := context.Background()
+
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if err := checkNotEmpty("credly.GetBadgeTemplatesByIds", "Template ID", id); err != nil {
+			return nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found := make(map[string]BadgeTemplate, len(unique))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	forEach(ctx, len(unique), maxConcurrentRequests, func(ctx context.Context, i int) {
+		t, err := c.getBadgeTemplate(ctx, "credly.GetBadgeTemplatesByIds", unique[i])
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[unique[i]] = err
+			return
+		}
+		found[unique[i]] = t
+	})
+
+	templates := make([]BadgeTemplate, 0, len(ids))
+	for _, id := range ids {
+		if t, ok := found[id]; ok {
+			templates = append(templates, t)
+		}
 	}
 
-	var badgeResp getBadgeTemplateResponse
-	if err := json.NewDecoder(resp.Body).Decode(&badgeResp); err != nil {
-		return b, fmt.Errorf("[credly.GetBadgeTemplate] Failed to parse JSON data: %v", err)
+	if len(errs) > 0 {
+		return templates, &LookupError{Op: "credly.GetBadgeTemplatesByIds", Errors: errs}
 	}
 
-	return badgeResp.Data, nil
+	return templates, nil
 }
 
-// GetBadgeTemplates retrieves all badge templates for the organization.
+// GetBadgeTemplates retrieves all badge templates for the organization, in
+// Credly's default order: to sort them, use GetBadgeTemplatesByFilter with
+// BadgeTemplateFilter.Sort.
+//
+// With lenient decoding enabled (see WithLenientDecoding), malformed templates are
+// skipped and reported in a *PartialDecodeError returned alongside the valid ones.
 //
 // Returns: A slice of BadgeTemplate representing all templates, or an error if the operation fails.
 func (c *Client) GetBadgeTemplates() (b []BadgeTemplate, err error) {
-	url := fmt.Sprintf("https://api.credly.com/v1/organizations/%s/badge_templates", c.OrganizationId)
+	url := c.withPageSize(fmt.Sprintf("%s/organizations/%s/badge_templates", c.baseURL(), c.OrganizationId))
+
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "credly.GetBadgeTemplates"), "GET", url, nil)
+	if err != nil {
+		return b, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return b, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return b, newAPIError("credly.GetBadgeTemplates", resp)
+	}
+
+	return decodeListResponse[BadgeTemplate]("credly.GetBadgeTemplates", resp.Body, c.lenientDecoding)
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// GetBadgeTemplateByVanitySlug retrieves a badge template by its vanity slug,
+// the human-readable name used in its public URL. Unlike the template ID, the
+// slug can be kept when a template is recreated.
+//
+// slug: The vanity slug of the badge template.
+// Returns: The matching BadgeTemplate, or ErrNotFound if no template has this slug.
+func (c *Client) GetBadgeTemplateByVanitySlug(slug string) (b BadgeTemplate, err error) {
+	if slug == "" {
+		return b, fmt.Errorf("[credly.GetBadgeTemplateByVanitySlug] Vanity slug must not be empty")
+	}
+
+	qUrl, err := c.filteredBadgeTemplatesUrl(BadgeTemplateFilter{VanitySlug: slug})
+	if err != nil {
+		return b, err
+	}
+
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "credly.GetBadgeTemplateByVanitySlug"), "GET", qUrl, nil)
 	if err != nil {
 		return b, err
 	}
@@ -88,13 +362,117 @@ func (c *Client) GetBadgeTemplates() (b []BadgeTemplate, err error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return b, fmt.Errorf("[credly.GetBadgeTemplates] API request failed with status code: %d", resp.StatusCode)
+		return b, newAPIError("credly.GetBadgeTemplateByVanitySlug", resp)
 	}
 
-	var badgeResp getBadgeTemplatesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&badgeResp); err != nil {
-		return b, fmt.Errorf("[credly.GetBadgeTemplates] Failed to parse JSON data: %v", err)
+	templates, err := decodeListResponse[BadgeTemplate]("credly.GetBadgeTemplateByVanitySlug", resp.Body, false)
+	if err != nil {
+		return b, err
+	}
+
+	// Only accept an exact match, in case the filter isn't applied as expected
+	for _, t := range templates {
+		if t.VanitySlug == slug {
+			return t, nil
+		}
 	}
 
-	return badgeResp.Data, nil
+	return b, fmt.Errorf("[credly.GetBadgeTemplateByVanitySlug] %w: no badge template with vanity slug %q", ErrNotFound, slug)
+}
+
+// GetBadgeTemplateSummaries retrieves the ID, name and image URL of all badge
+// templates for the organization, e.g. to populate a template picker. All pages
+// of templates are fetched.
+//
+// Only these fields are requested, using a JSON:API sparse fieldset. If Credly
+// ignores the fieldset, full templates are sent back and the extra fields are
+// discarded while decoding, so the result is the same with a larger payload.
+//
+// Returns: A slice of BadgeTemplateSummary, or an error if the operation fails.
+func (c *Client) GetBadgeTemplateSummaries() ([]BadgeTemplateSummary, error) {
+	qUrl := fmt.Sprintf("%s/organizations/%s/badge_templates", c.baseURL(), c.OrganizationId)
+	qUrl = withQueryParam(qUrl, "fields[badge_templates]", strings.Join(badgeTemplateSummaryFields, ","))
+
+	return listAll[BadgeTemplateSummary](context.Background(), c, "credly.GetBadgeTemplateSummaries", qUrl)
+}
+
+// GetCollections retrieves the collections defined for the organization, i.e.
+// the distinct reporting tags of its badge templates, e.g. to offer the valid
+// values of the collections parameter of GetBadges. All pages of templates are
+// fetched.
+//
+// Returns: The collections, sorted alphabetically, or an error if the operation fails.
+func (c *Client) GetCollections() ([]string, error) {
+	qUrl := fmt.Sprintf("%s/organizations/%s/badge_templates", c.baseURL(), c.OrganizationId)
+
+	templates, err := listAll[BadgeTemplate](context.Background(), c, "credly.GetCollections", qUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	collections := []string{}
+	for _, t := range templates {
+		for _, tag := range t.ReportingTags {
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				collections = append(collections, tag)
+			}
+		}
+	}
+	sort.Strings(collections)
+
+	return collections, nil
+}
+
+// updatableBadgeTemplateFields lists the fields of a badge template that can be changed with UpdateBadgeTemplate.
+var updatableBadgeTemplateFields = map[string]bool{
+	"name":        true,
+	"description": true,
+	"skills":      true,
+}
+
+// UpdateBadgeTemplate updates the properties of a badge template, e.g. to rename
+// a certification or change its skills. Badges already issued from the template
+// keep its ID.
+//
+// templateId: The ID of the badge template.
+// fields: The fields to update and their new values. Only name, description and
+// skills are accepted.
+// Returns: The updated BadgeTemplate, or an error if the operation fails.
+func (c *Client) UpdateBadgeTemplate(templateId string, fields map[string]interface{}) (b BadgeTemplate, err error) {
+	if len(fields) == 0 {
+		return b, fmt.Errorf("[credly.UpdateBadgeTemplate] No fields to update")
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !updatableBadgeTemplateFields[k] {
+			return b, fmt.Errorf("[credly.UpdateBadgeTemplate] Unknown or read-only field: %q", k)
+		}
+	}
+
+	url := fmt.Sprintf("%s/organizations/%s/badge_templates/%s", c.baseURL(), c.OrganizationId, templateId)
+
+	reqBody, err := json.Marshal(fields)
+	if err != nil {
+		return b, fmt.Errorf("[credly.UpdateBadgeTemplate] Failed to marshal parameters: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "credly.UpdateBadgeTemplate"), "PUT", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return b, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return b, err
+	}
+	defer resp.Body.Close()
+
+	return decodeResponse[BadgeTemplate]("credly.UpdateBadgeTemplate", resp, http.StatusOK)
 }
@@ -20,17 +20,29 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// getBadgeTemplateResponse represents the body of a Credly response returning a badge template.
+type getBadgeTemplateResponse struct {
+	Data BadgeTemplate `json:"data"`
+}
+
+// getBadgeTemplatesResponse represents the body of a Credly response listing badge templates.
+type getBadgeTemplatesResponse struct {
+	Data dataList[BadgeTemplate] `json:"data"`
+}
+
 func TestGetBadgeTemplate(t *testing.T) {
 	mockClient := new(MockHTTPClient)
 	client := &Client{
-		HTTPClient: mockClient,
-		authToken:  base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
 	}
 
 	templateId := "template-123"
@@ -58,11 +70,70 @@ func TestGetBadgeTemplate(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestGetBadgeTemplatesByIds(t *testing.T) {
+	client := &Client{OrganizationId: "org-123"}
+	client.HTTPClient = HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Path {
+		case "/v1/organizations/org-123/badge_templates/template-123", "/v1/organizations/org-123/badge_templates/template-456":
+			id := path.Base(req.URL.Path)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "` + id + `"}}`)),
+			}, nil
+		default:
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(bytes.NewBufferString("")),
+			}, nil
+		}
+	})
+
+	templates, err := client.GetBadgeTemplatesByIds([]string{"template-456", "template-123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeTemplate{{Id: "template-456"}, {Id: "template-123"}}, templates)
+
+	// Unknown templates are reported alongside the others
+	templates, err = client.GetBadgeTemplatesByIds([]string{"template-123", "unknown", "template-456"})
+
+	var lookupErr *LookupError
+	assert.ErrorAs(t, err, &lookupErr)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, lookupErr.Errors, "unknown")
+	assert.Equal(t, []BadgeTemplate{{Id: "template-123"}, {Id: "template-456"}}, templates)
+}
+
+func TestGetBadgeTemplatesByIds_InvalidId(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	templates, err := client.GetBadgeTemplatesByIds([]string{"template-123", ""})
+	assert.EqualError(t, err, "[credly.GetBadgeTemplatesByIds] Template ID must not be empty")
+	assert.Nil(t, templates)
+
+	_, err = client.GetBadgeTemplate(" ")
+	assert.EqualError(t, err, "[credly.GetBadgeTemplate] Template ID must not be empty")
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+
+	// IDs are escaped rather than altering the path
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.EscapedPath() == "/v1/organizations/org-123/badge_templates/template-123%2Fedit"
+	})).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil)
+
+	_, err = client.GetBadgeTemplatesByIds([]string{"template-123/edit"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
+
 func TestGetBadgeTemplates(t *testing.T) {
 	mockClient := new(MockHTTPClient)
 	client := &Client{
-		HTTPClient: mockClient,
-		authToken:  base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
 	}
 
 	expectedTemplates := []BadgeTemplate{
@@ -90,8 +161,9 @@ func TestGetBadgeTemplates(t *testing.T) {
 func TestGetBadgeTemplate_Failure(t *testing.T) {
 	mockClient := new(MockHTTPClient)
 	client := &Client{
-		HTTPClient: mockClient,
-		authToken:  base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
 	}
 
 	templateId := "template-123"
@@ -109,3 +181,298 @@ func TestGetBadgeTemplate_Failure(t *testing.T) {
 	assert.Empty(t, template)
 	mockClient.AssertExpectations(t)
 }
+
+func TestGetBadgeTemplateSummaries(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+	}
+
+	// Full templates are decoded into summaries if the fieldset is ignored
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data": []BadgeTemplate{
+			{Id: "template-123", Name: "Badge 1", ImageUrl: "http://image1.url", Skills: []string{"Go"}},
+		},
+		"metadata": PageMetadata{CurrentPage: 1, TotalPages: 2, NextPageUrl: "https://api.credly.com/v1/organizations/org-123/badge_templates?page=2"},
+	})
+	page2, _ := json.Marshal(map[string]interface{}{
+		"data": []BadgeTemplate{
+			{Id: "template-456", Name: "Badge 2", ImageUrl: "http://image2.url"},
+		},
+		"metadata": PageMetadata{CurrentPage: 2, TotalPages: 2},
+	})
+
+	// The fieldset is carried over to the following pages
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("fields[badge_templates]") == "id,name,image_url" &&
+			req.URL.Query().Get("page") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("fields[badge_templates]") == "id,name,image_url" &&
+			req.URL.Query().Get("page") == "2"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page2)),
+	}, nil).Once()
+
+	templates, err := client.GetBadgeTemplateSummaries()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeTemplateSummary{
+		{Id: "template-123", Name: "Badge 1", ImageUrl: "http://image1.url"},
+		{Id: "template-456", Name: "Badge 2", ImageUrl: "http://image2.url"},
+	}, templates)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgeTemplateByVanitySlug(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	responseBody, _ := json.Marshal(getBadgeTemplatesResponse{
+		Data: []BadgeTemplate{
+			{Id: "template-456", VanitySlug: "cilium-associate-beta"},
+			{Id: "template-123", VanitySlug: "cilium-associate"},
+		},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/organizations/org-123/badge_templates" &&
+			req.URL.Query().Get("filter") == "vanity_slug::cilium-associate"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil).Once()
+
+	template, err := client.GetBadgeTemplateByVanitySlug("cilium-associate")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "template-123", template.Id)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgeTemplateByVanitySlug_NotFound(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": []}`)),
+	}, nil).Once()
+
+	_, err := client.GetBadgeTemplateByVanitySlug("unknown")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockClient.AssertExpectations(t)
+
+	_, err = client.GetBadgeTemplateByVanitySlug("")
+	assert.Error(t, err)
+}
+
+func TestBadgeTemplateFilter_Encode(t *testing.T) {
+	f, err := BadgeTemplateFilter{}.Encode()
+	assert.NoError(t, err)
+	assert.Empty(t, f)
+
+	f, err = BadgeTemplateFilter{State: BadgeTemplateStateArchived, VanitySlug: "cilium-associate"}.Encode()
+	assert.NoError(t, err)
+	assert.Equal(t, "state::archived|vanity_slug::cilium-associate", f)
+
+	_, err = BadgeTemplateFilter{State: "hidden"}.Encode()
+	assert.Error(t, err)
+
+	_, err = BadgeTemplateFilter{VanitySlug: "a|b"}.Encode()
+	assert.Error(t, err)
+}
+
+func TestGetBadgeTemplatesByFilter_Sort(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "state::active" && req.URL.Query().Get("sort") == "name"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "template-123"}]}`)),
+	}, nil).Once()
+
+	templates, err := client.GetBadgeTemplatesByFilter(BadgeTemplateFilter{State: BadgeTemplateStateActive, Sort: "name"})
+
+	assert.NoError(t, err)
+	assert.Len(t, templates, 1)
+	mockClient.AssertExpectations(t)
+
+	_, err = client.GetBadgeTemplatesByFilter(BadgeTemplateFilter{Sort: "-issued_at"})
+	assert.ErrorContains(t, err, "Unknown sort key")
+}
+
+func TestGetActiveBadgeTemplates(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	responseBody, _ := json.Marshal(getBadgeTemplatesResponse{
+		Data: []BadgeTemplate{{Id: "template-123", State: BadgeTemplateStateActive}},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/organizations/org-123/badge_templates" &&
+			req.URL.Query().Get("filter") == "state::active"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil).Once()
+
+	templates, err := client.GetActiveBadgeTemplates()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeTemplate{{Id: "template-123", State: BadgeTemplateStateActive}}, templates)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetCollections(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data": []BadgeTemplate{
+			{Id: "template-123", ReportingTags: []string{"security", "networking"}},
+			{Id: "template-456"},
+		},
+		"metadata": PageMetadata{CurrentPage: 1, TotalPages: 2, NextPageUrl: "https://api.credly.com/v1/organizations/org-123/badge_templates?page=2"},
+	})
+	page2, _ := json.Marshal(map[string]interface{}{
+		"data": []BadgeTemplate{
+			{Id: "template-789", ReportingTags: []string{"networking", "ebpf"}},
+		},
+		"metadata": PageMetadata{CurrentPage: 2, TotalPages: 2},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("page") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("page") == "2"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page2)),
+	}, nil).Once()
+
+	collections, err := client.GetCollections()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ebpf", "networking", "security"}, collections)
+	mockClient.AssertExpectations(t)
+}
+
+func TestUpdateBadgeTemplate(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	expectedTemplate := BadgeTemplate{Id: "template-123", Name: "Cilium Certified Associate", Skills: []string{"eBPF"}}
+
+	responseBody, _ := json.Marshal(getBadgeTemplateResponse{
+		Data: expectedTemplate,
+	})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == "PUT" && req.URL.Path == "/v1/organizations/org-123/badge_templates/template-123"
+	})).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	template, err := client.UpdateBadgeTemplate("template-123", map[string]interface{}{
+		"name":   "Cilium Certified Associate",
+		"skills": []string{"eBPF"},
+	})
+
+	expectedTemplate.SkillDetails = []Skill{{Name: "eBPF"}}
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTemplate, template)
+	assert.Equal(t, map[string]interface{}{
+		"name":   "Cilium Certified Associate",
+		"skills": []interface{}{"eBPF"},
+	}, params)
+	mockClient.AssertExpectations(t)
+}
+
+func TestUpdateBadgeTemplate_InvalidFields(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	_, err := client.UpdateBadgeTemplate("template-123", nil)
+	assert.ErrorContains(t, err, "No fields to update")
+
+	_, err = client.UpdateBadgeTemplate("template-123", map[string]interface{}{"name": "New name", "vanity_slug": "new"})
+	assert.ErrorContains(t, err, `Unknown or read-only field: "vanity_slug"`)
+
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestBadgeTemplate_UnmarshalJSON(t *testing.T) {
+	body := `{
+		"id": "template-123",
+		"name": "Cilium Certified Associate",
+		"description": "Earners understand the fundamentals of Cilium.",
+		"level": "Intermediate",
+		"type_category": "Certification",
+		"time_to_earn": "Weeks",
+		"cost": "Paid",
+		"skills": ["eBPF", "Kubernetes"]
+	}`
+
+	var template BadgeTemplate
+	err := json.Unmarshal([]byte(body), &template)
+
+	assert.NoError(t, err)
+	assert.Equal(t, BadgeTemplate{
+		Id:           "template-123",
+		Name:         "Cilium Certified Associate",
+		Description:  "Earners understand the fundamentals of Cilium.",
+		Skills:       []string{"eBPF", "Kubernetes"},
+		SkillDetails: []Skill{{Name: "eBPF"}, {Name: "Kubernetes"}},
+		Level:        "Intermediate",
+		TypeCategory: "Certification",
+		TimeToEarn:   "Weeks",
+		Cost:         "Paid",
+	}, template)
+}
+
+func TestBadgeTemplate_UnmarshalJSON_SkillObjects(t *testing.T) {
+	body := `{
+		"id": "template-123",
+		"skills": [
+			{"id": "skill-1", "name": "eBPF", "vanity_url": "https://www.credly.com/skills/ebpf"},
+			{"id": "skill-2", "name": "Kubernetes", "vanity_url": "https://www.credly.com/skills/kubernetes"}
+		]
+	}`
+
+	var template BadgeTemplate
+	err := json.Unmarshal([]byte(body), &template)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"eBPF", "Kubernetes"}, template.Skills)
+	assert.Equal(t, []Skill{
+		{Id: "skill-1", Name: "eBPF", VanityUrl: "https://www.credly.com/skills/ebpf"},
+		{Id: "skill-2", Name: "Kubernetes", VanityUrl: "https://www.credly.com/skills/kubernetes"},
+	}, template.SkillDetails)
+
+	// Templates nested in badges are decoded the same way
+	var badge BadgeInfo
+	err = json.Unmarshal([]byte(`{"id": "badge-123", "badge_template": `+body+`}`), &badge)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "skill-2", badge.Template.SkillDetails[1].Id)
+}
@@ -16,10 +16,13 @@ package credly
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,11 +30,28 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// issueBadgeResponse represents the body of a Credly response returning an
+// issued badge, see https://www.credly.com/docs/issued_badges
+type issueBadgeResponse struct {
+	Data BadgeInfo `json:"data"`
+}
+
+// getBadgeResponse represents the body of a Credly response returning a badge.
+type getBadgeResponse struct {
+	Data BadgeInfo `json:"data"`
+}
+
+// getBadgesResponse represents the body of a Credly response listing badges.
+type getBadgesResponse struct {
+	Data dataList[BadgeInfo] `json:"data"`
+}
+
 func TestIssueBadge(t *testing.T) {
 	mockClient := new(MockHTTPClient)
 	client := &Client{
-		HTTPClient: mockClient,
-		authToken:  base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
 	}
 
 	templateId := "template-123"
@@ -72,8 +92,9 @@ func TestIssueBadge(t *testing.T) {
 func TestIssueBadge_BadgeAlreadyIssued(t *testing.T) {
 	mockClient := new(MockHTTPClient)
 	client := &Client{
-		HTTPClient: mockClient,
-		authToken:  base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
 	}
 
 	templateId := "template-123"
@@ -98,8 +119,9 @@ func TestIssueBadge_BadgeAlreadyIssued(t *testing.T) {
 func TestIssueBadge_Failure(t *testing.T) {
 	mockClient := new(MockHTTPClient)
 	client := &Client{
-		HTTPClient: mockClient,
-		authToken:  base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
 	}
 
 	templateId := "template-123"
@@ -124,8 +146,9 @@ func TestIssueBadge_Failure(t *testing.T) {
 func TestGetBadges_NoCollections(t *testing.T) {
 	mockClient := new(MockHTTPClient)
 	client := &Client{
-		HTTPClient: mockClient,
-		authToken:  base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
 	}
 
 	email := "test@example.com"
@@ -153,7 +176,7 @@ func TestGetBadges_NoCollections(t *testing.T) {
 
 func TestGetBadges_WithCollections(t *testing.T) {
 	mockClient := new(MockHTTPClient)
-	client := &Client{HTTPClient: mockClient}
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
 
 	email := "test@example.com"
 	collections := []string{"collection1", "collection2"}
@@ -182,8 +205,9 @@ func TestGetBadges_WithCollections(t *testing.T) {
 func TestGetBadge(t *testing.T) {
 	mockClient := new(MockHTTPClient)
 	client := &Client{
-		HTTPClient: mockClient,
-		authToken:  base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
 	}
 
 	email := "test@example.com"
@@ -210,11 +234,92 @@ func TestGetBadge(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestGetBadgeById(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+	}
+
+	expectedBadge := BadgeInfo{Id: "badge-123", State: "accepted"}
+
+	responseBody, _ := json.Marshal(getBadgeResponse{
+		Data: expectedBadge,
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/organizations/org-123/badges/badge-123" && req.URL.RawQuery == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	badge, err := client.GetBadgeById("badge-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedBadge, badge)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgeById_NotFound(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil)
+
+	badge, err := client.GetBadgeById("badge-404")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, err.Error(), "credly.GetBadgeById")
+	assert.Empty(t, badge)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgeById_EmptyId(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	badge, err := client.GetBadgeByIdContext(context.Background(), "")
+	assert.EqualError(t, err, "[credly.GetBadgeById] Badge ID must not be empty")
+	assert.Empty(t, badge)
+
+	badges, errs := client.GetBadgesByIds([]string{""})
+	assert.Empty(t, badges)
+	assert.EqualError(t, errs[""], "[credly.GetBadgeById] Badge ID must not be empty")
+
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestGetBadgeById_InvalidId(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	_, err := client.GetBadgeById(" ")
+	assert.EqualError(t, err, "[credly.GetBadgeById] Badge ID must not be empty")
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.EscapedPath() == "/v1/organizations/org-123/badges/..%2Fbadge-123"
+	})).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil)
+
+	_, err = client.GetBadgeById("../badge-123")
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
+
 func TestGetBadges_Failure(t *testing.T) {
 	mockClient := new(MockHTTPClient)
 	client := &Client{
-		HTTPClient: mockClient,
-		authToken:  base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
 	}
 
 	email := "test@example.com"
@@ -232,3 +337,1587 @@ func TestGetBadges_Failure(t *testing.T) {
 	assert.Empty(t, badges)
 	mockClient.AssertExpectations(t)
 }
+
+func TestGetBadgesByIds(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+	}
+
+	responseBody, _ := json.Marshal(getBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123", State: "accepted"},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/organizations/org-123/badges/badge-123"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil).Once()
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/organizations/org-123/badges/badge-404"
+	})).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	badges, errs := client.GetBadgesByIds([]string{"badge-123", "badge-404", "badge-123"})
+
+	assert.Equal(t, map[string]BadgeInfo{"badge-123": {Id: "badge-123", State: "accepted"}}, badges)
+	assert.Len(t, errs, 1)
+	assert.ErrorIs(t, errs["badge-404"], ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgesByIds_ContextCanceled(t *testing.T) {
+	client := &Client{
+		HTTPClient:     &http.Client{},
+		OrganizationId: "org-123",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	badges, errs := client.GetBadgesByIdsContext(ctx, []string{"badge-123", "badge-456"})
+
+	assert.Empty(t, badges)
+	assert.Len(t, errs, 2)
+	assert.ErrorIs(t, errs["badge-123"], context.Canceled)
+	assert.ErrorIs(t, errs["badge-456"], context.Canceled)
+}
+
+func TestIssueBadgeWithOptions_CustomFields(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+	}
+
+	expectedBadge := BadgeInfo{
+		Id:           "badge-123",
+		CustomFields: map[string]string{"score": "92"},
+	}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: expectedBadge,
+	})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	badge, err := client.IssueBadgeWithOptions("template-123", "test@example.com", "John", "Doe", IssueOptions{
+		CustomFields: map[string]string{"score": "92"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"score": "92"}, params["custom_fields"])
+	assert.Equal(t, expectedBadge.CustomFields, badge.CustomFields)
+	mockClient.AssertExpectations(t)
+}
+
+func TestIssueBadgeWithOptions_RecipientAttributes(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	attributes := map[string]string{"employee_id": "E-1234", "department": "Engineering"}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123", RecipientAttributes: attributes},
+	})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil).Once()
+
+	badge, err := client.IssueBadgeWithOptions("template-123", "test@example.com", "John", "Doe", IssueOptions{
+		RecipientAttributes: attributes,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"employee_id": "E-1234", "department": "Engineering"}, params["recipient_attributes"])
+	assert.Equal(t, attributes, badge.RecipientAttributes)
+	mockClient.AssertExpectations(t)
+
+	// Attribute names must not be empty
+	_, err = client.IssueBadgeWithOptions("template-123", "test@example.com", "John", "Doe", IssueOptions{
+		RecipientAttributes: map[string]string{"": "E-1234"},
+	})
+	assert.Error(t, err)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestIssueBadgeWithOptions_IdempotencyKey(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, MaxRetries: 1, RetryBaseDelay: time.Millisecond, OrganizationId: "org-123"}
+
+	key := IdempotencyKeyFor("template-123", "test@example.com")
+
+	// Retries carry the same key
+	withKey := mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Idempotency-Key") == key
+	})
+	mockClient.On("Do", withKey).Return(&http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+	mockClient.On("Do", withKey).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "badge-123"}}`)),
+	}, nil).Once()
+
+	badge, err := client.IssueBadgeWithOptions("template-123", "test@example.com", "John", "Doe", IssueOptions{
+		IdempotencyKey: key,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "badge-123", badge.Id)
+	mockClient.AssertExpectations(t)
+}
+
+func TestIdempotencyKeyFor(t *testing.T) {
+	key := IdempotencyKeyFor("template-123", "test@example.com")
+
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, IdempotencyKeyFor("template-123", " Test@Example.com "))
+	assert.NotEqual(t, key, IdempotencyKeyFor("template-456", "test@example.com"))
+	assert.NotEqual(t, key, IdempotencyKeyFor("template-123", "other@example.com"))
+}
+
+func TestIssueBadge_NoCustomFields(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+	}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	_, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+	assert.NoError(t, err)
+	assert.NotContains(t, params, "custom_fields")
+	assert.NotContains(t, params, "evidence")
+	assert.NotContains(t, params, "expires_at")
+	mockClient.AssertExpectations(t)
+}
+
+func TestIssueBadgeWithOptions_ExpiresAt(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	expiresAt := time.Date(2027, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123", ExpiresAt: expiresAt},
+	})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	badge, err := client.IssueBadgeWithOptions("template-123", "test@example.com", "John", "Doe", IssueOptions{
+		ExpiresAt: expiresAt,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2027-06-01 00:00:00 +0000", params["expires_at"])
+	assert.True(t, expiresAt.Equal(badge.ExpiresAt))
+	mockClient.AssertExpectations(t)
+}
+
+func TestIssueBadgeWithOptions_Evidence(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	_, err := client.IssueBadgeWithOptions("template-123", "test@example.com", "John", "Doe", IssueOptions{
+		Evidence: []Evidence{
+			{Type: EvidenceUrl, Title: "Exam result", Url: "https://exams.example.com/results/42"},
+			{Type: EvidenceId, Title: "Candidate ID", Description: "C-1234"},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "UrlEvidence", "title": "Exam result", "url": "https://exams.example.com/results/42"},
+		map[string]interface{}{"type": "IdEvidence", "title": "Candidate ID", "description": "C-1234"},
+	}, params["evidence"])
+	mockClient.AssertExpectations(t)
+}
+
+func TestIssueBadgeWithOptions_SuppressNotification(t *testing.T) {
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	for _, suppress := range []bool{false, true} {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		var params map[string]interface{}
+		mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+			req := args.Get(0).(*http.Request)
+			_ = json.NewDecoder(req.Body).Decode(&params)
+		}).Return(&http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(bytes.NewReader(responseBody)),
+		}, nil)
+
+		_, err := client.IssueBadgeWithOptions("template-123", "test@example.com", "John", "Doe", IssueOptions{
+			IssuedAt:             time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC),
+			SuppressNotification: suppress,
+		})
+
+		assert.NoError(t, err)
+		if suppress {
+			assert.Equal(t, true, params["suppress_badge_notification_email"])
+		} else {
+			assert.NotContains(t, params, "suppress_badge_notification_email")
+		}
+		mockClient.AssertExpectations(t)
+	}
+}
+
+func TestIssueBadgeWithOptions_UserId(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil).Once()
+
+	_, err := client.IssueBadgeWithOptions("template-123", "", "John", "Doe", IssueOptions{UserId: " user-123 "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-123", params["user_id"])
+	assert.NotContains(t, params, "recipient_email")
+	mockClient.AssertExpectations(t)
+}
+
+func TestIssueBadgeWithOptions_UserIdAndEmail(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	_, err := client.IssueBadgeWithOptions("template-123", "test@example.com", "John", "Doe", IssueOptions{UserId: "user-123"})
+	assert.ErrorContains(t, err, "Exactly one of email or user ID must be set")
+
+	_, err = client.IssueBadgeWithOptions("template-123", "", "John", "Doe", IssueOptions{})
+	assert.ErrorIs(t, err, ErrInvalidEmail)
+
+	_, err = client.IssueBadgeWithOptions("template-123", "", "", "Doe", IssueOptions{UserId: "user-123"})
+	assert.ErrorContains(t, err, "First and last names must not be empty")
+
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestIssueBadgeWithOptions_EmptyCustomFieldName(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	badge, err := client.IssueBadgeWithOptions("template-123", "test@example.com", "John", "Doe", IssueOptions{
+		CustomFields: map[string]string{"": "92"},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Custom field names must not be empty")
+	assert.Empty(t, badge)
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestIssueBadge_UnicodeNames(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+	}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	var body []byte
+	mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		body, _ = io.ReadAll(req.Body)
+	}).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	// "José" is a decomposed (NFD) form and must not be normalized
+	_, err := client.IssueBadge("template-123", "test@example.com", "José José", "Müller")
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "\"issued_to_first_name\":\"Jos\xc3\xa9 Jose\xcc\x81\"")
+	assert.Contains(t, string(body), "\"issued_to_last_name\":\"M\xc3\xbcller\"")
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadges_LenientDecoding(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithLenientDecoding())
+	client.HTTPClient = mockClient
+
+	responseBody := `{"data": [{"id": "badge-123"}, {"id": "badge-456", "issued_at": "not-a-time"}, {"id": "badge-789"}]}`
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+	}, nil)
+
+	badges, err := client.GetBadges("test@example.com", nil)
+
+	var decodeErr *PartialDecodeError
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.Len(t, decodeErr.Items, 1)
+	assert.Equal(t, 1, decodeErr.Items[0].Index)
+	assert.Contains(t, string(decodeErr.Items[0].Raw), "badge-456")
+	assert.Equal(t, []BadgeInfo{{Id: "badge-123"}, {Id: "badge-789"}}, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadges_StrictDecoding(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	responseBody := `{"data": [{"id": "badge-123"}, {"id": "badge-456", "issued_at": "not-a-time"}]}`
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(responseBody)),
+	}, nil)
+
+	badges, err := client.GetBadges("test@example.com", nil)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to parse JSON data")
+	assert.Empty(t, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestIssueBadge_IssuedAtTimezone(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		suffix string
+	}{
+		{name: "default UTC", suffix: " +0000"},
+		{name: "configured zone", opts: []Option{WithTimezone(time.FixedZone("IST", 5*3600+1800))}, suffix: " +0530"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockHTTPClient)
+			client := NewClient("test-token", "org-123", tt.opts...)
+			client.HTTPClient = mockClient
+
+			responseBody, _ := json.Marshal(issueBadgeResponse{
+				Data: BadgeInfo{Id: "badge-123"},
+			})
+
+			var params map[string]interface{}
+			mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+				req := args.Get(0).(*http.Request)
+				_ = json.NewDecoder(req.Body).Decode(&params)
+			}).Return(&http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(bytes.NewReader(responseBody)),
+			}, nil)
+
+			_, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+			assert.NoError(t, err)
+			issuedAt := params["issued_at"].(string)
+			assert.True(t, strings.HasSuffix(issuedAt, tt.suffix), issuedAt)
+			_, err = time.Parse(timeLayout, issuedAt)
+			assert.NoError(t, err)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIssueBadgeAt(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	passed := time.Date(2021, time.March, 14, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	badge, err := client.IssueBadgeAt("template-123", "test@example.com", "John", "Doe", passed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "badge-123", badge.Id)
+	assert.Equal(t, "2021-03-14 08:30:00 +0000", params["issued_at"])
+	mockClient.AssertExpectations(t)
+}
+
+func TestEnsureBadge(t *testing.T) {
+	existing, _ := json.Marshal(getBadgesResponse{Data: []BadgeInfo{{Id: "badge-123"}}})
+	none, _ := json.Marshal(getBadgesResponse{Data: []BadgeInfo{}})
+	issued, _ := json.Marshal(issueBadgeResponse{Data: BadgeInfo{Id: "badge-456"}})
+
+	respond := func(status int, body []byte) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader(body))}
+	}
+	isMethod := func(method string) interface{} {
+		return mock.MatchedBy(func(req *http.Request) bool { return req.Method == method })
+	}
+
+	t.Run("already exists", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", isMethod("GET")).Return(respond(http.StatusOK, existing), nil).Once()
+
+		badge, created, err := client.EnsureBadge("template-123", "test@example.com", "John", "Doe")
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, "badge-123", badge.Id)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("newly issued", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", isMethod("GET")).Return(respond(http.StatusOK, none), nil).Once()
+		mockClient.On("Do", isMethod("POST")).Return(respond(http.StatusCreated, issued), nil).Once()
+
+		badge, created, err := client.EnsureBadge("template-123", "test@example.com", "John", "Doe")
+
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "badge-456", badge.Id)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("issued concurrently", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", isMethod("GET")).Return(respond(http.StatusOK, none), nil).Once()
+		mockClient.On("Do", isMethod("POST")).Return(respond(http.StatusUnprocessableEntity, nil), nil).Once()
+		mockClient.On("Do", isMethod("GET")).Return(respond(http.StatusOK, existing), nil).Once()
+
+		badge, created, err := client.EnsureBadge("template-123", "test@example.com", "John", "Doe")
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, "badge-123", badge.Id)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("normalized recipient", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		// The lookup uses the trimmed email sent at issuance
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Method == "GET" && strings.Contains(req.URL.Query().Get("filter"), "recipient_email_all::test@example.com|")
+		})).Return(respond(http.StatusOK, none), nil).Once()
+		mockClient.On("Do", isMethod("POST")).Return(respond(http.StatusCreated, issued), nil).Once()
+
+		_, created, err := client.EnsureBadge("template-123", "  test@example.com ", " John", "Doe ")
+
+		assert.NoError(t, err)
+		assert.True(t, created)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		_, _, err := client.EnsureBadge("template-123", "not-an-email", "John", "Doe")
+
+		assert.ErrorIs(t, err, ErrInvalidEmail)
+		assert.ErrorContains(t, err, "[credly.EnsureBadge]")
+		mockClient.AssertNotCalled(t, "Do", mock.Anything)
+	})
+}
+
+func TestIssueOrGetBadge(t *testing.T) {
+	existing, _ := json.Marshal(getBadgesResponse{Data: []BadgeInfo{{Id: "badge-123"}}})
+	none, _ := json.Marshal(getBadgesResponse{Data: []BadgeInfo{}})
+	issued, _ := json.Marshal(issueBadgeResponse{Data: BadgeInfo{Id: "badge-456"}})
+
+	respond := func(status int, body []byte) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader(body))}
+	}
+	isMethod := func(method string) interface{} {
+		return mock.MatchedBy(func(req *http.Request) bool { return req.Method == method })
+	}
+
+	t.Run("newly issued", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		// No lookup is needed
+		mockClient.On("Do", isMethod("POST")).Return(respond(http.StatusCreated, issued), nil).Once()
+
+		badge, created, err := client.IssueOrGetBadge("template-123", "test@example.com", "John", "Doe")
+
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "badge-456", badge.Id)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("already issued", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", isMethod("POST")).Return(respond(http.StatusUnprocessableEntity, nil), nil).Once()
+		mockClient.On("Do", isMethod("GET")).Return(respond(http.StatusOK, existing), nil).Once()
+
+		badge, created, err := client.IssueOrGetBadge("template-123", "test@example.com", "John", "Doe")
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, "badge-123", badge.Id)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("already issued but not found", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", isMethod("POST")).Return(respond(http.StatusUnprocessableEntity, nil), nil).Once()
+		mockClient.On("Do", isMethod("GET")).Return(respond(http.StatusOK, none), nil).Once()
+
+		_, created, err := client.IssueOrGetBadge("template-123", "test@example.com", "John", "Doe")
+
+		assert.ErrorContains(t, err, "already issued but could not be found")
+		assert.False(t, created)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", isMethod("POST")).Return(respond(http.StatusUnprocessableEntity, []byte(`{"message": "Badge template is not active"}`)), nil).Once()
+
+		_, created, err := client.IssueOrGetBadge("template-123", "test@example.com", "John", "Doe")
+
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.False(t, created)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestGetActiveBadges(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+	}
+
+	active := BadgeInfo{Id: "badge-123", State: "accepted"}
+	notExpired := BadgeInfo{Id: "badge-456", State: "accepted", ExpiresAt: time.Now().Add(time.Hour).UTC().Truncate(time.Second)}
+	expired := BadgeInfo{Id: "badge-789", State: "accepted", ExpiresAt: time.Now().Add(-time.Hour)}
+
+	responseBody, _ := json.Marshal(getBadgesResponse{
+		Data: []BadgeInfo{active, notExpired, expired},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "recipient_email_all::test@example.com|state::accepted"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	badges, err := client.GetActiveBadges("test@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeInfo{active, notExpired}, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestBadgeInfo_IsActive(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, BadgeInfo{State: "accepted"}.IsActive(now))
+	assert.True(t, BadgeInfo{State: "accepted", ExpiresAt: now.Add(time.Hour)}.IsActive(now))
+	assert.False(t, BadgeInfo{State: "accepted", ExpiresAt: now.Add(-time.Hour)}.IsActive(now))
+	assert.False(t, BadgeInfo{State: "pending"}.IsActive(now))
+	assert.False(t, BadgeInfo{State: "revoked"}.IsActive(now))
+
+	assert.False(t, BadgeInfo{}.IsExpired(now))
+	assert.True(t, BadgeInfo{ExpiresAt: now.Add(-time.Second)}.IsExpired(now))
+}
+
+func TestGetBadges_Pagination(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+	}
+
+	expectedFilter := "recipient_email_all::test@example.com|badge_templates[reporting_tags]::collection1,collection2"
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-123"}},
+		"metadata": PageMetadata{CurrentPage: 1, TotalPages: 2, NextPageUrl: "https://api.credly.com/v1/organizations/org-123/badges?page=2"},
+	})
+	page2, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-456"}},
+		"metadata": PageMetadata{CurrentPage: 2, TotalPages: 2},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == expectedFilter && req.URL.Query().Get("page") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+
+	// The filter is preserved on subsequent pages
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == expectedFilter && req.URL.Query().Get("page") == "2"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page2)),
+	}, nil).Once()
+
+	badges, err := client.GetBadges("test@example.com", []string{"collection1", "collection2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeInfo{{Id: "badge-123"}, {Id: "badge-456"}}, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgesPage(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+	}
+
+	metadata := PageMetadata{
+		Count:           1,
+		CurrentPage:     2,
+		TotalCount:      3,
+		TotalPages:      3,
+		PerPage:         1,
+		PreviousPageUrl: "https://api.credly.com/v1/organizations/org-123/badges?page=1",
+		NextPageUrl:     "https://api.credly.com/v1/organizations/org-123/badges?page=3",
+	}
+	responseBody, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-456"}},
+		"metadata": metadata,
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("page") == "2" &&
+			req.URL.Query().Get("filter") == "recipient_email_all::test@example.com"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	badges, meta, err := client.GetBadgesPage("test@example.com", nil, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeInfo{{Id: "badge-456"}}, badges)
+	assert.Equal(t, metadata, meta)
+	mockClient.AssertExpectations(t)
+}
+
+func TestUpdateBadge(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+	}
+
+	expectedBadge := BadgeInfo{Id: "badge-123", State: "accepted"}
+	responseBody, _ := json.Marshal(getBadgeResponse{Data: expectedBadge})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == "PUT" && req.URL.Path == "/v1/organizations/org-123/badges/badge-123"
+	})).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	badge, err := client.UpdateBadge("badge-123", map[string]interface{}{
+		"issued_to_first_name": "Jon",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedBadge, badge)
+	assert.Equal(t, map[string]interface{}{"issued_to_first_name": "Jon"}, params)
+	mockClient.AssertExpectations(t)
+}
+
+func TestUpdateBadge_InvalidFields(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	_, err := client.UpdateBadge("badge-123", map[string]interface{}{
+		"issued_to_first_name": "Jon",
+		"state":                "revoked",
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `Unknown or read-only field: "state"`)
+
+	_, err = client.UpdateBadge("badge-123", nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "No fields to update")
+
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestIssueBadge_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		message string
+		errs    []string
+	}{
+		{
+			name:    "invalid email",
+			body:    `{"message": "Recipient email is invalid", "errors": ["recipient_email is not a valid email"]}`,
+			message: "Recipient email is invalid",
+			errs:    []string{"recipient_email is not a valid email"},
+		},
+		{
+			name:    "unknown template",
+			body:    `{"data": {"message": "Badge template not found"}}`,
+			message: "Badge template not found",
+		},
+		{
+			name:    "errors only",
+			body:    `{"errors": [{"attribute": "issued_to_first_name", "message": "can't be blank"}, "issued_at is invalid"]}`,
+			message: "can't be blank; issued_at is invalid",
+			errs:    []string{"can't be blank", "issued_at is invalid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockHTTPClient)
+			client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+			mockClient.On("Do", mock.Anything).Return(&http.Response{
+				StatusCode: http.StatusUnprocessableEntity,
+				Body:       io.NopCloser(bytes.NewBufferString(tt.body)),
+			}, nil)
+
+			_, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+			assert.ErrorIs(t, err, ErrValidation)
+			assert.NotErrorIs(t, err, ErrAlreadyIssued)
+
+			var apiErr *APIError
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.message, apiErr.Message)
+			assert.Equal(t, tt.errs, apiErr.Errors)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestIssueBadge_AlreadyIssuedMessage(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"message": "Badge has already been issued to this recipient"}`)),
+	}, nil)
+
+	_, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+	assert.ErrorIs(t, err, ErrAlreadyIssued)
+	assert.Contains(t, err.Error(), "Badge has already been issued to this recipient")
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+}
+
+func TestGetBadgesByTemplate(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+	}
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-123"}, {Id: "badge-456"}},
+		"metadata": PageMetadata{CurrentPage: 1, TotalPages: 2, NextPageUrl: "https://api.credly.com/v1/organizations/org-123/badges?filter=badge_template_id%3A%3Atemplate-123&page=2"},
+	})
+	page2, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-789"}},
+		"metadata": PageMetadata{CurrentPage: 2, TotalPages: 2},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "badge_template_id::template-123" && req.URL.Query().Get("page") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "badge_template_id::template-123" && req.URL.Query().Get("page") == "2"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page2)),
+	}, nil).Once()
+
+	badges, err := client.GetBadgesByTemplate("template-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeInfo{{Id: "badge-123"}, {Id: "badge-456"}, {Id: "badge-789"}}, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgesByState(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+	}
+
+	responseBody, _ := json.Marshal(getBadgesResponse{
+		Data: []BadgeInfo{{Id: "badge-123", State: BadgeStateIssued}},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "recipient_email_all::test@example.com|state::issued"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	badges, err := client.GetBadgesByState("test@example.com", BadgeStateIssued)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeInfo{{Id: "badge-123", State: BadgeStateIssued}}, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgesByState_UnknownState(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	badges, err := client.GetBadgesByState("test@example.com", "archived")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unknown badge state")
+	assert.Empty(t, badges)
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestBadgeInfo_UnmarshalJSON(t *testing.T) {
+	body := `{
+		"id": "badge-123",
+		"state": "revoked",
+		"issued_at": "2024-01-10T09:00:00.000-05:00",
+		"accepted_at": "2024-01-11T10:00:00.000-05:00",
+		"expires_at": null,
+		"revoked_at": "2024-03-01T12:00:00.000-05:00",
+		"revocation_reason": "Exam voided",
+		"public": true,
+		"issuer": {"entities": []}
+	}`
+
+	var badge BadgeInfo
+	err := json.Unmarshal([]byte(body), &badge)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-01-11T15:00:00Z", badge.AcceptedAt.UTC().Format(time.RFC3339))
+	assert.Equal(t, "2024-03-01T17:00:00Z", badge.RevokedAt.UTC().Format(time.RFC3339))
+	assert.True(t, badge.ExpiresAt.IsZero())
+	assert.Equal(t, "Exam voided", badge.RevocationReason)
+	assert.True(t, badge.Public)
+}
+
+func TestBadgeInfo_UnmarshalJSON_TimeFormats(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: `"2024-01-10T09:00:00.000-05:00"`, expected: time.Date(2024, time.January, 10, 14, 0, 0, 0, time.UTC)},
+		{value: `"2024-01-10T14:00:00Z"`, expected: time.Date(2024, time.January, 10, 14, 0, 0, 0, time.UTC)},
+		{value: `"2024-01-10 09:00:00 -0500"`, expected: time.Date(2024, time.January, 10, 14, 0, 0, 0, time.UTC)},
+		{value: `"2024-01-10T09:00:00-0500"`, expected: time.Date(2024, time.January, 10, 14, 0, 0, 0, time.UTC)},
+		{value: `"2024-01-10 14:00:00 UTC"`, expected: time.Date(2024, time.January, 10, 14, 0, 0, 0, time.UTC)},
+		{value: `"2024-01-10 14:00:00"`, expected: time.Date(2024, time.January, 10, 14, 0, 0, 0, time.UTC)},
+		{value: `"2024-01-10"`, expected: time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)},
+		{value: `""`, expected: time.Time{}},
+		{value: `null`, expected: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var badge BadgeInfo
+			err := json.Unmarshal([]byte(`{"id": "badge-123", "issued_at": `+tt.value+`}`), &badge)
+
+			assert.NoError(t, err)
+			assert.True(t, tt.expected.Equal(badge.IssuedAt), "got %v", badge.IssuedAt)
+			assert.Nil(t, badge.Extra)
+		})
+	}
+
+	var badge BadgeInfo
+	err := json.Unmarshal([]byte(`{"issued_at": "10/01/2024"}`), &badge)
+	assert.ErrorContains(t, err, "Unknown timestamp format")
+
+	err = json.Unmarshal([]byte(`{"issued_at": 1704895200}`), &badge)
+	assert.ErrorContains(t, err, "Invalid timestamp")
+}
+
+func TestBadgeInfo_UnmarshalJSON_Extra(t *testing.T) {
+	body := `{
+		"id": "badge-123",
+		"state": "accepted",
+		"locale": "en",
+		"evidence": [{"type": "IdEvidence", "id": "ev-1"}]
+	}`
+
+	var badge BadgeInfo
+	err := json.Unmarshal([]byte(body), &badge)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "badge-123", badge.Id)
+	assert.Equal(t, map[string]json.RawMessage{
+		"locale":   json.RawMessage(`"en"`),
+		"evidence": json.RawMessage(`[{"type": "IdEvidence", "id": "ev-1"}]`),
+	}, badge.Extra)
+
+	// Badges without unmapped fields have no Extra
+	badge = BadgeInfo{}
+	err = json.Unmarshal([]byte(`{"id": "badge-456"}`), &badge)
+
+	assert.NoError(t, err)
+	assert.Nil(t, badge.Extra)
+}
+
+func TestIssuer_UnmarshalJSON(t *testing.T) {
+	body := `{
+		"id": "badge-123",
+		"issuer": {
+			"summary": "issued by Isovalent",
+			"entities": [
+				{"label": "Authorized by", "primary": false, "entity": {"type": "Organization", "id": "org-456", "name": "Linux Foundation", "vanity_url": "https://www.credly.com/org/linux-foundation"}},
+				{"label": "Issued by", "primary": true, "entity": {"type": "Organization", "id": "org-123", "name": "Isovalent", "vanity_url": "https://www.credly.com/org/isovalent"}}
+			]
+		}
+	}`
+
+	var badge BadgeInfo
+	err := json.Unmarshal([]byte(body), &badge)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Issuer{Id: "org-123", Name: "Isovalent", VanityUrl: "https://www.credly.com/org/isovalent"}, badge.Issuer)
+
+	// Flat objects are accepted too
+	var issuer Issuer
+	err = json.Unmarshal([]byte(`{"id": "org-789", "name": "Cisco"}`), &issuer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Issuer{Id: "org-789", Name: "Cisco"}, issuer)
+}
+
+func TestIssueBadge_InvalidRecipient(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		firstName string
+		lastName  string
+		err       error
+	}{
+		{name: "empty email", email: "", firstName: "John", lastName: "Doe", err: ErrInvalidEmail},
+		{name: "missing domain", email: "john.doe", firstName: "John", lastName: "Doe", err: ErrInvalidEmail},
+		{name: "display name", email: "John Doe <test@example.com>", firstName: "John", lastName: "Doe", err: ErrInvalidEmail},
+		{name: "empty first name", email: "test@example.com", firstName: " ", lastName: "Doe"},
+		{name: "empty last name", email: "test@example.com", firstName: "John", lastName: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockHTTPClient)
+			client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+			_, err := client.IssueBadge("template-123", tt.email, tt.firstName, tt.lastName)
+
+			assert.Error(t, err)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			mockClient.AssertNotCalled(t, "Do", mock.Anything)
+		})
+	}
+}
+
+func TestIssueBadge_TrimsRecipient(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	_, err := client.IssueBadge("template-123", " test@example.com\t", " John", "Doe ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", params["recipient_email"])
+	assert.Equal(t, "John", params["issued_to_first_name"])
+	assert.Equal(t, "Doe", params["issued_to_last_name"])
+	mockClient.AssertExpectations(t)
+}
+
+func TestRevokeBadge(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	responseBody, _ := json.Marshal(getBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123", State: BadgeStateRevoked, RevocationReason: "Exam voided"},
+	})
+
+	var params map[string]interface{}
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == "PUT" && req.URL.Path == "/v1/organizations/org-123/badges/badge-123/revoke"
+	})).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		_ = json.NewDecoder(req.Body).Decode(&params)
+	}).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	badge, err := client.RevokeBadge("badge-123", "Exam voided")
+
+	assert.NoError(t, err)
+	assert.Equal(t, BadgeStateRevoked, badge.State)
+	assert.Equal(t, map[string]interface{}{"reason": "Exam voided"}, params)
+	mockClient.AssertExpectations(t)
+}
+
+func TestReplaceBadge(t *testing.T) {
+	revoked, _ := json.Marshal(getBadgeResponse{Data: BadgeInfo{Id: "badge-123", State: BadgeStateRevoked}})
+	issued, _ := json.Marshal(issueBadgeResponse{Data: BadgeInfo{Id: "badge-456"}})
+
+	respond := func(status int, body []byte) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader(body))}
+	}
+	isMethod := func(method string) interface{} {
+		return mock.MatchedBy(func(req *http.Request) bool { return req.Method == method })
+	}
+
+	t.Run("replaced", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", isMethod("PUT")).Return(respond(http.StatusOK, revoked), nil).Once()
+		mockClient.On("Do", isMethod("POST")).Return(respond(http.StatusCreated, issued), nil).Once()
+
+		badge, err := client.ReplaceBadge("badge-123", "template-456", "test@example.com", "John", "Doe")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "badge-456", badge.Id)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("issuance fails after revocation", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", isMethod("PUT")).Return(respond(http.StatusOK, revoked), nil).Once()
+		mockClient.On("Do", isMethod("POST")).Return(respond(http.StatusNotFound, nil), nil).Once()
+
+		_, err := client.ReplaceBadge("badge-123", "template-456", "test@example.com", "John", "Doe")
+
+		var replaceErr *ReplaceBadgeError
+		assert.ErrorAs(t, err, &replaceErr)
+		assert.Equal(t, "badge-123", replaceErr.OldBadgeId)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Contains(t, err.Error(), "badge-123 was revoked")
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("revocation fails", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", isMethod("PUT")).Return(respond(http.StatusNotFound, nil), nil).Once()
+
+		_, err := client.ReplaceBadge("badge-123", "template-456", "test@example.com", "John", "Doe")
+
+		var replaceErr *ReplaceBadgeError
+		assert.False(t, errors.As(err, &replaceErr))
+		assert.ErrorIs(t, err, ErrNotFound)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		_, err := client.ReplaceBadge("badge-123", "template-456", "not-an-email", "John", "Doe")
+
+		assert.ErrorIs(t, err, ErrInvalidEmail)
+		mockClient.AssertNotCalled(t, "Do", mock.Anything)
+	})
+}
+
+func TestAcceptBadge(t *testing.T) {
+	accepted, _ := json.Marshal(getBadgeResponse{Data: BadgeInfo{Id: "badge-123", State: BadgeStateAccepted}})
+
+	t.Run("pending", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Method == "PUT" && req.URL.Path == "/v1/organizations/org-123/badges/badge-123/accept"
+		})).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(accepted)),
+		}, nil).Once()
+
+		badge, err := client.AcceptBadge("badge-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, BadgeStateAccepted, badge.State)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("already accepted", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Method == "PUT"
+		})).Return(&http.Response{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"message": "Badge is not pending"}`)),
+		}, nil).Once()
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Method == "GET" && req.URL.Path == "/v1/organizations/org-123/badges/badge-123"
+		})).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(accepted)),
+		}, nil).Once()
+
+		badge, err := client.AcceptBadge("badge-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, BadgeStateAccepted, badge.State)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestRejectBadge_AlreadyRevoked(t *testing.T) {
+	revoked, _ := json.Marshal(getBadgeResponse{Data: BadgeInfo{Id: "badge-123", State: BadgeStateRevoked}})
+
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == "PUT" && req.URL.Path == "/v1/organizations/org-123/badges/badge-123/reject"
+	})).Return(&http.Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"message": "Badge is not pending"}`)),
+	}, nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == "GET"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(revoked)),
+	}, nil).Once()
+
+	_, err := client.RejectBadge("badge-123")
+
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Contains(t, err.Error(), "Badge is not pending")
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgesForEmails(t *testing.T) {
+	client := &Client{OrganizationId: "org-123"}
+	client.HTTPClient = HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Query().Get("filter") {
+		case "recipient_email_all::alice@example.com":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "badge-123"}, {"id": "badge-456"}]}`)),
+			}, nil
+		case "recipient_email_all::bob@example.com":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"data": []}`)),
+			}, nil
+		default:
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       io.NopCloser(bytes.NewBufferString("")),
+			}, nil
+		}
+	})
+
+	badges, err := client.GetBadgesForEmails([]string{"alice@example.com", "bob@example.com", "carol@example.com", "alice@example.com"}, 2)
+
+	// A failed lookup doesn't abort the others
+	var lookupErr *LookupError
+	assert.ErrorAs(t, err, &lookupErr)
+	assert.Len(t, lookupErr.Errors, 1)
+	assert.Contains(t, lookupErr.Errors, "carol@example.com")
+	assert.Contains(t, err.Error(), "carol@example.com")
+
+	assert.Len(t, badges, 2)
+	assert.Len(t, badges["alice@example.com"], 2)
+	assert.Empty(t, badges["bob@example.com"])
+	assert.Contains(t, badges, "bob@example.com")
+}
+
+func TestGetBadgesForEmailsContext_Cancelled(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mockClient.On("Do", mock.Anything).Return((*http.Response)(nil), context.Canceled).Maybe()
+
+	badges, err := client.GetBadgesForEmailsContext(ctx, []string{"alice@example.com", "bob@example.com"}, 0)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, badges)
+}
+
+func TestResendBadgeNotification(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == "POST" && req.URL.Path == "/v1/organizations/org-123/badges/badge-123/resend"
+	})).Return(&http.Response{
+		StatusCode: http.StatusNoContent,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	err := client.ResendBadgeNotification("badge-123")
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestResendBadgeNotification_Revoked(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"message": "Badge has been revoked"}`)),
+	}, nil).Once()
+
+	err := client.ResendBadgeNotification("badge-123")
+
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Contains(t, err.Error(), "Badge has been revoked")
+	mockClient.AssertExpectations(t)
+}
+
+func TestDeleteBadge(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == "DELETE" && req.URL.Path == "/v1/organizations/org-123/badges/badge-123"
+	})).Return(&http.Response{
+		StatusCode: http.StatusNoContent,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	err := client.DeleteBadge("badge-123")
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDeleteBadge_Refused(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"message": "Accepted badges cannot be deleted"}`)),
+	}, nil).Once()
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	err := client.DeleteBadge("badge-123")
+
+	assert.ErrorIs(t, err, ErrBadgeNotDeletable)
+	assert.Contains(t, err.Error(), "Accepted badges cannot be deleted")
+
+	err = client.DeleteBadge("badge-456")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgesByCollection(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	// Only the collection is filtered, without recipient
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/organizations/org-123/badges" &&
+			req.URL.Query().Get("filter") == "badge_templates[reporting_tags]::security"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "badge-123"}, {"id": "badge-456"}]}`)),
+	}, nil).Once()
+
+	badges, err := client.GetBadgesByCollection("security")
+
+	assert.NoError(t, err)
+	assert.Len(t, badges, 2)
+	mockClient.AssertExpectations(t)
+
+	_, err = client.GetBadgesByCollection("")
+	assert.ErrorContains(t, err, "Collection must not be empty")
+
+	_, err = client.GetBadgesByCollection("a,b")
+	assert.Error(t, err)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestSearchBadges(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-123"}},
+		"metadata": PageMetadata{CurrentPage: 1, TotalPages: 2, NextPageUrl: "https://api.credly.com/v1/organizations/org-123/badges?page=2"},
+	})
+	page2, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-456"}},
+		"metadata": PageMetadata{CurrentPage: 2, TotalPages: 2},
+	})
+
+	// The query is preserved on subsequent pages
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("query") == "john doe" && req.URL.Query().Get("sort") == "-issued_at"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("query") == "john doe" && req.URL.Query().Get("page") == "2"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page2)),
+	}, nil).Once()
+
+	badges, err := client.SearchBadges(" john doe ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeInfo{{Id: "badge-123"}, {Id: "badge-456"}}, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSearchBadges_EmptyQuery(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	_, err := client.SearchBadges("  ")
+
+	assert.Error(t, err)
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestWithDryRun(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithDryRun())
+	client.HTTPClient = mockClient
+
+	issuedAt := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	badge, err := client.IssueBadgeWithOptions("template-123", " test@example.com", "John", "Doe", IssueOptions{
+		IssuedAt:     issuedAt,
+		CustomFields: map[string]string{"score": "92"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, badge.DryRun)
+	assert.Equal(t, "dry-run", badge.Id)
+	assert.Equal(t, "template-123", badge.Template.Id)
+	assert.Equal(t, "test@example.com", badge.User.Email)
+	assert.Equal(t, issuedAt, badge.IssuedAt)
+	assert.Equal(t, map[string]string{"score": "92"}, badge.CustomFields)
+
+	// Parameters are still validated
+	_, err = client.IssueBadge("template-123", "not-an-email", "John", "Doe")
+	assert.ErrorIs(t, err, ErrInvalidEmail)
+
+	// The old badge isn't revoked
+	_, err = client.ReplaceBadge("badge-123", "template-456", "test@example.com", "John", "Doe")
+	assert.NoError(t, err)
+
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestGetBadge_NotFound(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	responseBody, _ := json.Marshal(getBadgesResponse{
+		Data: []BadgeInfo{},
+	})
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	badge, err := client.GetBadge("test@example.com", "template-123")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Empty(t, badge)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadge_Failure(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, MaxRetries: 0, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": []}`)),
+	}, nil)
+
+	_, err := client.GetBadge("test@example.com", "template-123")
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	assert.NotErrorIs(t, err, ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadge_InvalidJSON(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`<html>Maintenance</html>`)),
+	}, nil)
+
+	_, err := client.GetBadge("test@example.com", "template-123")
+
+	assert.ErrorContains(t, err, "[credly.GetBadge]")
+	assert.ErrorContains(t, err, "(body: <html>Maintenance</html>)")
+	assert.NotErrorIs(t, err, ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgeContext_Cancelled(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Context().Err() != nil
+	})).Return((*http.Response)(nil), context.Canceled).Maybe()
+
+	_, err := client.GetBadgeContext(ctx, "test@example.com", "template-123")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
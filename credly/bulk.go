@@ -0,0 +1,125 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"strings"
+)
+
+// Recipient identifies the recipient of a badge issued in bulk.
+type Recipient struct {
+	Email     string
+	FirstName string
+	LastName  string
+}
+
+// BulkIssueOptions holds optional settings for IssueBadges.
+type BulkIssueOptions struct {
+	// IssueOptions are applied to every badge issued.
+	IssueOptions
+
+	// Deduplicate skips recipients whose email, ignoring case and surrounding
+	// whitespace, already appears earlier in the list.
+	Deduplicate bool
+}
+
+// BulkIssueResult holds the outcome of issuing a badge to one recipient.
+type BulkIssueResult struct {
+	// Recipient is the recipient the badge was issued to.
+	Recipient Recipient
+
+	// Badge is the issued badge, if issuance succeeded.
+	Badge BadgeInfo
+
+	// Err is the error returned by Credly, if issuance failed.
+	Err error
+
+	// Duplicate is true if the recipient was skipped as a duplicate of an
+	// earlier entry. No request is made for duplicates.
+	Duplicate bool
+}
+
+// BulkIssueResults holds the outcome of issuing a badge to several
+// recipients, one BulkIssueResult per recipient, see IssueBadges.
+type BulkIssueResults []BulkIssueResult
+
+// Err returns a *BulkError listing the recipients for which issuance failed,
+// or nil if it succeeded for all of them. Duplicates don't count as failures.
+func (r BulkIssueResults) Err() error {
+	e := &BulkError{Op: "credly.IssueBadges"}
+	for _, result := range r {
+		switch {
+		case result.Err != nil:
+			e.Failures = append(e.Failures, BulkFailure{Email: result.Recipient.Email, Err: result.Err})
+		case !result.Duplicate:
+			e.Succeeded++
+		}
+	}
+
+	if len(e.Failures) == 0 {
+		return nil
+	}
+	return e
+}
+
+// IssueBadges issues a badge to several recipients.
+// See IssueBadgesContext.
+func (c *Client) IssueBadges(templateId string, recipients []Recipient, opts BulkIssueOptions) BulkIssueResults {
+	return c.IssueBadgesContext(context.Background(), templateId, recipients, opts)
+}
+
+// IssueBadgesContext issues a badge to several recipients, with a bounded
+// number of requests in flight at once. A failure for one recipient doesn't
+// prevent issuance to the others.
+//
+// ctx: The context controlling the requests. Cancelling it aborts pending issuances.
+// templateId: The ID of the badge template to be issued.
+// recipients: The recipients of the badge.
+// opts: Optional settings, see BulkIssueOptions.
+// Returns: One BulkIssueResult per recipient, in the same order as recipients.
+// Use BulkIssueResults.Err to get a single error for the failed recipients.
+func (c *Client) IssueBadgesContext(ctx context.Context, templateId string, recipients []Recipient, opts BulkIssueOptions) BulkIssueResults {
+	results := make(BulkIssueResults, len(recipients))
+	pending := make([]int, 0, len(recipients))
+	seen := make(map[string]bool, len(recipients))
+
+	for i, r := range recipients {
+		results[i].Recipient = r
+
+		if opts.Deduplicate {
+			key := strings.ToLower(strings.TrimSpace(r.Email))
+			if seen[key] {
+				results[i].Duplicate = true
+				continue
+			}
+			seen[key] = true
+		}
+
+		pending = append(pending, i)
+	}
+
+	forEach(ctx, len(pending), maxConcurrentRequests, func(ctx context.Context, n int) {
+		i := pending[n]
+		r := recipients[i]
+		o := opts.IssueOptions
+		if o.IdempotencyKey != "" {
+			o.IdempotencyKey = deriveKey(o.IdempotencyKey, strings.ToLower(strings.TrimSpace(r.Email)))
+		}
+		results[i].Badge, results[i].Err = c.issueBadge(ctx, templateId, r.Email, r.FirstName, r.LastName, o)
+	})
+
+	return results
+}
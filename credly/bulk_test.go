@@ -0,0 +1,160 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIssueBadges_Deduplicate(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+	}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil).Once()
+
+	results := client.IssueBadges("template-123", []Recipient{
+		{Email: "test@example.com", FirstName: "John", LastName: "Doe"},
+		{Email: " Test@Example.com ", FirstName: "John", LastName: "Doe"},
+	}, BulkIssueOptions{Deduplicate: true})
+
+	assert.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.False(t, results[0].Duplicate)
+	assert.Equal(t, "badge-123", results[0].Badge.Id)
+	assert.True(t, results[1].Duplicate)
+	assert.Empty(t, results[1].Badge)
+	assert.Equal(t, " Test@Example.com ", results[1].Recipient.Email)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+	mockClient.AssertExpectations(t)
+}
+
+func TestIssueBadges_PartialFailure(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+	}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	isRecipient := func(email string) interface{} {
+		return mock.MatchedBy(func(req *http.Request) bool {
+			var params map[string]interface{}
+			body, _ := req.GetBody()
+			_ = json.NewDecoder(body).Decode(&params)
+			return params["recipient_email"] == email
+		})
+	}
+
+	mockClient.On("Do", isRecipient("ok@example.com")).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil).Once()
+	mockClient.On("Do", isRecipient("dup@example.com")).Return(&http.Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Twice()
+
+	// Without de-duplication, every entry is issued
+	results := client.IssueBadges("template-123", []Recipient{
+		{Email: "ok@example.com", FirstName: "John", LastName: "Doe"},
+		{Email: "dup@example.com", FirstName: "Jane", LastName: "Doe"},
+		{Email: "dup@example.com", FirstName: "Jane", LastName: "Doe"},
+	}, BulkIssueOptions{})
+
+	assert.Len(t, results, 3)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "badge-123", results[0].Badge.Id)
+	assert.ErrorIs(t, results[1].Err, ErrAlreadyIssued)
+	assert.ErrorIs(t, results[2].Err, ErrAlreadyIssued)
+	assert.False(t, results[2].Duplicate)
+	mockClient.AssertExpectations(t)
+
+	// The failures are aggregated in a single error
+	err := results.Err()
+
+	var bulkErr *BulkError
+	assert.ErrorAs(t, err, &bulkErr)
+	assert.Equal(t, 1, bulkErr.Succeeded)
+	assert.Len(t, bulkErr.Failures, 2)
+	assert.Equal(t, "dup@example.com", bulkErr.Failures[0].Email)
+	assert.ErrorIs(t, err, ErrAlreadyIssued)
+	assert.Contains(t, err.Error(), "Failed to issue 2 badge(s), 1 succeeded, first dup@example.com")
+}
+
+func TestBulkIssueResults_Err(t *testing.T) {
+	results := BulkIssueResults{
+		{Recipient: Recipient{Email: "ok@example.com"}},
+		{Recipient: Recipient{Email: "ok@example.com"}, Duplicate: true},
+	}
+
+	assert.NoError(t, results.Err())
+	assert.NoError(t, BulkIssueResults{}.Err())
+}
+
+func TestIssueBadges_IdempotencyKey(t *testing.T) {
+	client := &Client{OrganizationId: "org-123"}
+
+	var mu sync.Mutex
+	keys := map[string]string{}
+	client.HTTPClient = HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+		var params map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&params)
+
+		mu.Lock()
+		keys[params["recipient_email"].(string)] = req.Header.Get("Idempotency-Key")
+		mu.Unlock()
+
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "badge-123"}}`)),
+		}, nil
+	})
+
+	client.IssueBadges("template-123", []Recipient{
+		{Email: "john@example.com", FirstName: "John", LastName: "Doe"},
+		{Email: "jane@example.com", FirstName: "Jane", LastName: "Doe"},
+	}, BulkIssueOptions{IssueOptions: IssueOptions{IdempotencyKey: "backfill-2024"}})
+
+	// Each recipient gets its own key, stable across runs
+	assert.Len(t, keys, 2)
+	assert.NotEmpty(t, keys["john@example.com"])
+	assert.NotEqual(t, keys["john@example.com"], keys["jane@example.com"])
+	assert.NotEqual(t, "backfill-2024", keys["john@example.com"])
+	assert.Equal(t, deriveKey("backfill-2024", "john@example.com"), keys["john@example.com"])
+}
@@ -17,8 +17,18 @@
 package credly
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
 )
 
 // HTTPClientInterface defines the methods that http.Client and MockHTTPClient must implement.
@@ -38,40 +48,657 @@ type Client struct {
 
 	// OrganizationId is the unique identifier for the organization in Credly.
 	OrganizationId string
+
+	// BaseURL is the root URL of the Credly API. Defaults to DefaultBaseURL when empty.
+	BaseURL string
+
+	// UserAgent is sent in the User-Agent header so Credly can identify the
+	// caller. Defaults to DefaultUserAgent when empty.
+	UserAgent string
+
+	// MaxRetries is the number of times a request is retried after a 429 or 5xx
+	// response or a transient network error, see WithRetryPolicy. Zero, the
+	// default, disables retries: a retried write may be applied twice, e.g.
+	// issue a badge twice, if Credly processed it before failing.
+	MaxRetries int
+
+	// RetryBaseDelay is the delay before the first retry. It doubles on each
+	// subsequent retry, with jitter, unless Credly sends a Retry-After header.
+	RetryBaseDelay time.Duration
+
+	// environments holds the named credential sets selectable with Env.
+	environments map[string]Environment
+
+	// middleware is the chain of Middleware wrapping request execution in Do.
+	middleware []Middleware
+
+	// lenientDecoding makes list methods decode items individually, see WithLenientDecoding.
+	lenientDecoding bool
+
+	// location is the time zone used to format timestamps sent to Credly, see WithTimezone.
+	location *time.Location
+
+	// compression requests compressed responses, see WithCompression.
+	compression bool
+
+	// headers are added to every request, see WithDefaultHeader.
+	headers http.Header
+
+	// dryRun simulates badge issuance, see WithDryRun.
+	dryRun bool
+
+	// timeout is the timeout of the HTTP client created by NewClient, see WithTimeout.
+	timeout time.Duration
+
+	// roundTripper is the transport of the HTTP client created by NewClient, see WithTransport. Nil means the default transport.
+	roundTripper http.RoundTripper
+
+	// insecureSkipVerify disables the verification of TLS certificates, see WithInsecureSkipVerify.
+	insecureSkipVerify bool
+
+	// http2 enables or disables HTTP/2, see WithHTTP2. Nil leaves the transport's setting.
+	http2 *bool
+
+	// pageSize is the number of items requested per page by list methods, see WithPageSize. Zero means Credly's default.
+	pageSize int
+
+	// retryPolicy decides which failed requests are retried, see WithRetryPolicy. Nil means DefaultRetryPolicy.
+	retryPolicy RetryPolicy
+
+	// limiter spaces out requests, see WithRateLimit. Nil means no limit.
+	limiter *rateLimiter
+
+	// adaptive bounds the requests in flight based on the API health, see WithAdaptiveConcurrency. Nil means no bound.
+	adaptive *adaptiveLimiter
+
+	// auth refreshes rejected credentials, see WithAuthRefresh. Nil means no refresh.
+	auth *authRefresher
+
+	// tokens provides bearer tokens, see WithTokenSource. Nil means basic authentication with authToken.
+	tokens *tokenCache
+
+	// rateLimit holds the last rate limit state reported by Credly, see LastRateLimit.
+	rateLimit *rateLimitTracker
+
+	// requestId holds the last request ID reported by Credly, see LastRequestId.
+	requestId *requestIdTracker
+
+	// logger receives logs of the requests, see WithLogger. Nil means no logging.
+	logger Logger
+
+	// metrics receives measurements of the requests, see WithMetrics. Nil means no metrics.
+	metrics MetricsRecorder
+
+	// tracer traces the requests, see WithTracer. Nil means no tracing.
+	tracer Tracer
+}
+
+// HTTPClientFunc is an adapter allowing the use of an ordinary function as an
+// HTTPClientInterface.
+type HTTPClientFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f HTTPClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the execution of a request, allowing integrators to add
+// cross-cutting behavior (header injection, metrics, custom auth refresh...)
+// around every call made by the Client.
+//
+// A Middleware receives the next layer of the chain and returns a new layer.
+// It may inspect or modify the request, short-circuit the call, or inspect
+// the response returned by next.
+type Middleware func(next HTTPClientInterface) HTTPClientInterface
+
+// Option configures a Client created with NewClient.
+type Option func(*Client)
+
+// WithMiddleware appends middleware to the Client's request chain.
+//
+// Middleware are applied in the order given: the first one is the outermost
+// layer and sees the request first and the response last. The authentication
+// and content-type headers are set by the innermost layer, right before the
+// request is handed to HTTPClient, so middleware cannot see or override them.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
 }
 
-// ErrBadgeAlreadyIssued indicates that a badge has already been issued to the user.
+// DefaultBaseURL is the root URL of the production Credly API.
+const DefaultBaseURL = "https://api.credly.com/v1"
+
+// SandboxBaseURL is the root URL of the Credly sandbox API.
+const SandboxBaseURL = "https://sandbox-api.credly.com/v1"
+
+// ErrBadgeAlreadyIssued is the message of the error returned when a badge has
+// already been issued to the user and Credly doesn't give one. Compare errors
+// with ErrAlreadyIssued instead.
 const ErrBadgeAlreadyIssued = "User already has this badge"
 
+// ErrAlreadyIssued indicates that a badge has already been issued to the user.
+var ErrAlreadyIssued = errors.New(ErrBadgeAlreadyIssued)
+
+// ErrInvalidEmail indicates that a recipient email address is malformed. It is
+// detected before sending the request to Credly.
+var ErrInvalidEmail = errors.New("Invalid email address")
+
+// ErrNotFound indicates that the requested resource does not exist in Credly.
+var ErrNotFound = errors.New("Resource not found")
+
+// ErrUnauthorized indicates that Credly rejected the credentials of the Client,
+// or that they don't grant access to the requested resource.
+var ErrUnauthorized = errors.New("Unauthorized")
+
+// ErrValidation indicates that Credly rejected the parameters of a request.
+// APIError.Message and APIError.Errors describe what was invalid.
+var ErrValidation = errors.New("Invalid parameters")
+
+// ErrRateLimited indicates that Credly is throttling the Client's requests.
+var ErrRateLimited = errors.New("Rate limit exceeded")
+
+// ErrServiceUnavailable indicates that Credly is temporarily unavailable, e.g.
+// during a maintenance window. The request can be retried later, after the
+// delay given in APIError.RetryAfter if any.
+var ErrServiceUnavailable = errors.New("Service unavailable")
+
+// ErrBadgeRevoked indicates that a badge was revoked by its issuer, see VerifyBadge.
+var ErrBadgeRevoked = errors.New("Badge has been revoked")
+
+// ErrBadgeExpired indicates that a badge is past its expiration date, see VerifyBadge.
+var ErrBadgeExpired = errors.New("Badge has expired")
+
+// ErrBadgeNotDeletable indicates that Credly refused to delete a badge, e.g.
+// because it was already accepted or issued too long ago, see DeleteBadge.
+var ErrBadgeNotDeletable = errors.New("Badge can't be deleted")
+
+// ErrMissingOrganizationId indicates that the Client has no OrganizationId,
+// e.g. because of a missing environment variable. It is detected before sending
+// the request to Credly, which would otherwise answer with a confusing 404.
+var ErrMissingOrganizationId = errors.New("Missing organization ID")
+
+// ErrUnknownEnvironment indicates that no environment with the requested name was configured.
+var ErrUnknownEnvironment = errors.New("Unknown environment")
+
+// DefaultUserAgent is the User-Agent header sent when none is configured. It
+// includes the version of this module when available from the build info.
+var DefaultUserAgent = defaultUserAgent()
+
+// defaultUserAgent returns "credly-go/<version>", or "credly-go" if the module
+// version is unknown.
+func defaultUserAgent() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/isovalent/credly-go" && dep.Version != "" {
+				return "credly-go/" + dep.Version
+			}
+		}
+	}
+	return "credly-go"
+}
+
+// WithUserAgent sets the User-Agent header sent with every request, e.g. to
+// identify the calling service to Credly support. Defaults to DefaultUserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// userAgent returns the configured User-Agent, or DefaultUserAgent if none is set.
+func (c *Client) userAgent() string {
+	if c.UserAgent == "" {
+		return DefaultUserAgent
+	}
+	return c.UserAgent
+}
+
+// maxConcurrentRequests bounds the number of requests sent in parallel by batch helpers.
+const maxConcurrentRequests = 5
+
+// defaultTimeout is the default timeout of the HTTP client created by NewClient.
+const defaultTimeout = 30 * time.Second
+
+// WithHTTPClient sets the HTTP client used to send requests, e.g. to share a
+// connection pool or configure a proxy. Its timeout is left unchanged.
+func WithHTTPClient(hc HTTPClientInterface) Option {
+	return func(c *Client) {
+		c.HTTPClient = hc
+	}
+}
+
+// WithTransport sets the transport of the HTTP client created by NewClient,
+// e.g. one going through a corporate proxy or trusting a custom CA. The
+// timeout set with WithTimeout and the authentication headers set by the
+// Client are unaffected, whatever the order of the options.
+//
+// It has no effect when the HTTP client is replaced, e.g. with WithHTTPClient,
+// whose own transport is used.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.roundTripper = rt
+	}
+}
+
+// WithTimeout sets the timeout of the HTTP client created by NewClient,
+// covering the whole exchange including reading the response body. Zero means
+// no timeout. Defaults to 30 seconds, including when the transport is set with
+// WithTransport.
+//
+// It has no effect when the HTTP client is replaced, e.g. with WithHTTPClient,
+// whose own timeout is respected.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithDefaultHeader adds a header to every request, e.g. a feature flag
+// requested by Credly. Setting the same key again replaces its value. It can't
+// override the headers set by the client, such as Authorization. See
+// ContextWithHeader for headers specific to a request.
+func WithDefaultHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		c.headers.Set(key, value)
+	}
+}
+
+// headerKey is the context key holding the headers added by ContextWithHeader.
+type headerKey struct{}
+
+// ContextWithHeader returns a copy of ctx that adds a header to the requests
+// sent with it by the Context variants of the client methods, e.g. an
+// X-Correlation-ID tying the requests to a trace. Like WithDefaultHeader, it
+// can't override the headers set by the client, and it takes precedence over
+// default headers with the same key.
+func ContextWithHeader(ctx context.Context, key, value string) context.Context {
+	h := http.Header{}
+	if parent, ok := ctx.Value(headerKey{}).(http.Header); ok {
+		h = parent.Clone()
+	}
+	h.Set(key, value)
+
+	return context.WithValue(ctx, headerKey{}, h)
+}
+
+// setHeaders sets the default headers and those from the request context on req.
+func (c *Client) setHeaders(req *http.Request) {
+	for k, v := range c.headers {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if h, ok := req.Context().Value(headerKey{}).(http.Header); ok {
+		for k, v := range h {
+			req.Header[k] = append([]string(nil), v...)
+		}
+	}
+}
+
+// WithDryRun makes the client validate badge issuance requests and build them,
+// but not send them, e.g. to test an onboarding flow against production
+// without emailing recipients. Issuing methods return a synthetic badge with
+// DryRun set, and ReplaceBadge doesn't revoke the old badge. Other requests,
+// including those reading badges, are sent as usual.
+func WithDryRun() Option {
+	return func(c *Client) {
+		c.dryRun = true
+	}
+}
+
+// WithBaseURL sets the root URL of the Credly API, e.g. SandboxBaseURL or the
+// URL of a mock server in integration tests. Defaults to DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = baseURL
+	}
+}
+
+// Environment is a named set of credentials and endpoint, e.g. for a sandbox
+// and a production Credly organization, see WithEnvironment.
+type Environment struct {
+	// Token is the API token provided by Credly for authentication.
+	Token string
+
+	// OrganizationId is the unique identifier for the organization in Credly.
+	OrganizationId string
+
+	// BaseURL is the root URL of the Credly API. Defaults to DefaultBaseURL when empty.
+	BaseURL string
+}
+
+// WithEnvironment registers a named environment that can be selected with Env.
+func WithEnvironment(name string, env Environment) Option {
+	return func(c *Client) {
+		if c.environments == nil {
+			c.environments = make(map[string]Environment)
+		}
+		c.environments[name] = env
+	}
+}
+
+// Env returns a Client for the named environment registered with WithEnvironment.
+// The returned Client shares the HTTP client and settings of c, but uses the
+// token, organization and base URL of the environment.
+//
+// name: The name of the environment.
+// Returns: A Client for the environment, or ErrUnknownEnvironment if it wasn't registered.
+func (c *Client) Env(name string) (*Client, error) {
+	env, ok := c.environments[name]
+	if !ok {
+		return nil, fmt.Errorf("[credly.Env] %w: %q", ErrUnknownEnvironment, name)
+	}
+
+	sub := *c
+	sub.authToken = encodeToken(env.Token)
+	sub.auth = nil
+	sub.tokens = nil
+	sub.OrganizationId = env.OrganizationId
+	sub.BaseURL = env.BaseURL
+	if c.rateLimit != nil {
+		sub.rateLimit = &rateLimitTracker{}
+	}
+	if c.requestId != nil {
+		sub.requestId = &requestIdTracker{}
+	}
+
+	return &sub, nil
+}
+
+// baseURL returns the root URL of the Credly API used by the Client.
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return strings.TrimSuffix(c.BaseURL, "/")
+}
+
+// WithTimezone sets the time zone used to format timestamps sent to Credly,
+// such as the issued_at date of new badges. Defaults to UTC, so that records
+// don't depend on the time zone of the machine running the client.
+func WithTimezone(loc *time.Location) Option {
+	return func(c *Client) {
+		c.location = loc
+	}
+}
+
+// timezone returns the time zone used to format timestamps sent to Credly.
+func (c *Client) timezone() *time.Location {
+	if c.location == nil {
+		return time.UTC
+	}
+	return c.location
+}
+
+// WithCompression makes the client ask Credly for gzip or deflate compressed
+// responses by setting the Accept-Encoding header. Compressed responses are
+// decompressed by the client, and a corrupt stream is reported as a
+// *DecompressionError.
+func WithCompression() Option {
+	return func(c *Client) {
+		c.compression = true
+	}
+}
+
+// WithLenientDecoding makes list methods decode each item of a response
+// individually, so that a single malformed record doesn't fail the whole call.
+// Items that can't be decoded are skipped and reported in a *PartialDecodeError,
+// returned together with the items that decoded successfully.
+func WithLenientDecoding() Option {
+	return func(c *Client) {
+		c.lenientDecoding = true
+	}
+}
+
+// WithHTTP2 explicitly enables or disables HTTP/2 on the default transport.
+//
+// With HTTP/2, concurrent requests to api.credly.com are multiplexed over a
+// single connection. This saves connection setups, but a slow stream or lost
+// packet stalls every request sharing the connection (head-of-line blocking),
+// which shows during bulk operations. Disabling HTTP/2 makes the client use a
+// pool of HTTP/1.1 connections instead, trading more connections for isolation
+// between requests.
+//
+// The option only applies when HTTPClient is an *http.Client using an
+// *http.Transport (or the default transport), whatever the order of the
+// options. It is applied to a copy of the transport, leaving a transport or
+// HTTP client shared with the caller unchanged.
+func WithHTTP2(enabled bool) Option {
+	return func(c *Client) {
+		c.http2 = &enabled
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the server's TLS
+// certificate, e.g. to reach a mock Credly server using a self-signed
+// certificate in an integration test, along with WithBaseURL.
+//
+// WARNING: this is for testing only. Without verification, anyone on the
+// network path can impersonate Credly and capture the API token. Prefer adding
+// the test CA to the trusted roots with WithTransport where possible.
+//
+// The option only applies when HTTPClient is an *http.Client using an
+// *http.Transport (or the default transport), whatever the order of the
+// options. It is applied to a copy of the transport, leaving a transport or
+// HTTP client shared with the caller unchanged.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) {
+		c.insecureSkipVerify = true
+	}
+}
+
 // NewClient creates a new instance of the Credly API client.
 // It accepts an API token and the organization ID, returning a Client
 // with an encoded authentication token and organization-specific settings.
 //
+// The HTTP client is set with options, so a Client using a custom HTTP client,
+// e.g. a mock in tests, is built with:
+//
+//	client := credly.NewClient(token, organizationId, credly.WithHTTPClient(hc))
+//
 // token: The API token provided by Credly for authentication.
 // organizationId: The unique identifier for the organization in Credly.
+// opts: Optional settings applied to the Client.
 // Returns: A new Client instance configured for Credly API interaction.
-func NewClient(token, organizationId string) *Client {
-	// Encode the token with base64 and append a separator "|"
-	encodedToken := base64.StdEncoding.EncodeToString([]byte(token + "|"))
-
-	return &Client{
-		HTTPClient:     &http.Client{},
-		authToken:      encodedToken,
+func NewClient(token, organizationId string, opts ...Option) *Client {
+	hc := &http.Client{}
+	c := &Client{
+		HTTPClient:     hc,
+		authToken:      encodeToken(token),
 		OrganizationId: organizationId,
+		BaseURL:        DefaultBaseURL,
+		UserAgent:      DefaultUserAgent,
+		rateLimit:      &rateLimitTracker{},
+		requestId:      &requestIdTracker{},
+		RetryBaseDelay: defaultRetryBaseDelay,
+		timeout:        defaultTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.HTTPClient == hc {
+		hc.Timeout = c.timeout
+		hc.Transport = c.roundTripper
+	}
+	c.tuneTransport()
+
+	return c
+}
+
+// tuneTransport applies the transport settings of the options, once they have
+// all been applied, to a copy of the transport of the Client's HTTP client. The
+// HTTP client is copied as well, so that neither is changed for the caller if
+// shared. It does nothing if HTTPClient doesn't use an *http.Transport.
+func (c *Client) tuneTransport() {
+	if !c.insecureSkipVerify && c.http2 == nil {
+		return
+	}
+
+	hc, ok := c.HTTPClient.(*http.Client)
+	if !ok {
+		return
+	}
+
+	var t *http.Transport
+	switch rt := hc.Transport.(type) {
+	case nil:
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		t = rt.Clone()
+	default:
+		return
+	}
+
+	if c.insecureSkipVerify {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
 	}
+	if c.http2 != nil {
+		t.ForceAttemptHTTP2 = *c.http2
+		if *c.http2 {
+			t.TLSNextProto = nil
+		} else {
+			// A non-nil, empty map disables HTTP/2 negotiation
+			t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		}
+	}
+
+	tuned := *hc
+	tuned.Transport = t
+	c.HTTPClient = &tuned
+}
+
+// encodeToken encodes an API token for the Authorization header.
+func encodeToken(token string) string {
+	// Encode the token with base64 and append a separator "|"
+	return base64.StdEncoding.EncodeToString([]byte(token + "|"))
 }
 
 // Do sends an HTTP request using the Client's HTTP client, adding the necessary
-// authentication headers for the Credly API.
+// authentication headers for the Credly API. The request goes through the
+// configured middleware chain before reaching HTTPClient.
+//
+// A request body that can't be replayed, i.e. req.GetBody is nil, is first
+// buffered in memory and req.GetBody set, so that the body can be resent on
+// retries and read by middleware without consuming it.
+//
+// Requests fail with ErrMissingOrganizationId without being sent if the Client
+// has no OrganizationId.
 //
 // req: The HTTP request to be sent.
 // Returns: The HTTP response and any error encountered.
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
-	// Add the required headers for Credly API authentication and content type.
-	req.Header.Set("Authorization", "Basic "+c.authToken)
+	if c.OrganizationId == "" && !isPublic(req.Context()) {
+		return nil, fmt.Errorf("[credly.%s] %w", endpoint(req.Context()), ErrMissingOrganizationId)
+	}
+
+	if err := bufferBody(req); err != nil {
+		return nil, err
+	}
+
+	var h HTTPClientInterface = HTTPClientFunc(c.sendWithAuthRefresh)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+
+	return h.Do(req)
+}
+
+// bufferBody reads the body of req into memory and sets req.GetBody, unless
+// the body is empty or can already be replayed.
+func bufferBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("[credly] Failed to read request body: %w", err)
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+	req.ContentLength = int64(len(body))
+
+	return nil
+}
+
+// send is the innermost layer of the request chain.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Add the required headers for Credly API authentication and content type,
+	// after the custom ones so that they can't be overridden.
+	c.setHeaders(req)
+	if !isPublic(req.Context()) {
+		authorization, err := c.authorization(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", authorization)
+	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent())
+	if c.compression {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+
+	if c.adaptive != nil {
+		if err := c.adaptive.acquire(req.Context()); err != nil {
+			return nil, err
+		}
+	}
 
 	// Execute the HTTP request using the client's HTTP client.
-	return c.HTTPClient.Do(req)
+	req, endSpan := c.startSpan(req)
+	c.logRequest(req)
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	if c.adaptive != nil {
+		c.adaptive.release(resp, err, time.Since(start))
+	}
+	c.logResponse(req, resp, err, start)
+	c.observeRequest(req, resp, start)
+	endSpan(resp, err)
+	if err != nil {
+		return resp, err
+	}
+
+	c.rateLimit.observe(resp, time.Now())
+	c.requestId.observe(resp)
+
+	return decompressResponse(resp)
+}
+
+// forEach calls fn for every index in [0, n), running at most limit calls concurrently.
+// It returns once all calls have completed.
+func forEach(ctx context.Context, n, limit int, fn func(ctx context.Context, i int)) {
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(ctx, i)
+		}(i)
+	}
+
+	wg.Wait()
 }
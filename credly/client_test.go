@@ -15,9 +15,16 @@
 package credly
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
+	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -47,8 +54,9 @@ func TestNewClient(t *testing.T) {
 func TestDo(t *testing.T) {
 	mockHTTPClient := new(MockHTTPClient)
 	client := &Client{
-		HTTPClient: mockHTTPClient,
-		authToken:  base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+		HTTPClient:     mockHTTPClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
 	}
 
 	req, err := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
@@ -69,6 +77,418 @@ func TestDo(t *testing.T) {
 	assert.Equal(t, "Basic "+client.authToken, req.Header.Get("Authorization"))
 	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
 	assert.Equal(t, "application/json", req.Header.Get("Accept"))
+	assert.Equal(t, DefaultUserAgent, req.Header.Get("User-Agent"))
 
 	mockHTTPClient.AssertExpectations(t)
 }
+
+func TestDo_Middleware(t *testing.T) {
+	mockHTTPClient := new(MockHTTPClient)
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next HTTPClientInterface) HTTPClientInterface {
+			return HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+":before")
+				// Auth headers are only set by the innermost layer
+				assert.Empty(t, req.Header.Get("Authorization"))
+				req.Header.Set("X-"+name, "1")
+				resp, err := next.Do(req)
+				calls = append(calls, name+":after")
+				return resp, err
+			})
+		}
+	}
+
+	client := NewClient("test-token", "org-123", WithMiddleware(trace("First"), trace("Second")))
+	client.HTTPClient = mockHTTPClient
+
+	req, err := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
+	assert.NoError(t, err)
+
+	mockHTTPClient.On("Do", mock.MatchedBy(func(r *http.Request) bool {
+		return r.Header.Get("X-First") == "1" &&
+			r.Header.Get("X-Second") == "1" &&
+			r.Header.Get("Authorization") == "Basic "+client.authToken
+	})).Return(&http.Response{StatusCode: 200}, nil)
+
+	_, err = client.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"First:before", "Second:before", "Second:after", "First:after"}, calls)
+	mockHTTPClient.AssertExpectations(t)
+}
+
+func TestDo_MiddlewareReadsBody(t *testing.T) {
+	mockHTTPClient := new(MockHTTPClient)
+
+	var captured string
+	capture := func(next HTTPClientInterface) HTTPClientInterface {
+		return HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := req.GetBody()
+			assert.NoError(t, err)
+			payload, _ := io.ReadAll(body)
+			captured = string(payload)
+			return next.Do(req)
+		})
+	}
+
+	client := NewClient("test-token", "org-123", WithMiddleware(capture))
+	client.HTTPClient = mockHTTPClient
+
+	var sent string
+	mockHTTPClient.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		payload, _ := io.ReadAll(args.Get(0).(*http.Request).Body)
+		sent = string(payload)
+	}).Return(&http.Response{StatusCode: 200}, nil)
+
+	req, err := http.NewRequest("POST", "https://api.credly.com/v1/some-endpoint", io.NopCloser(strings.NewReader(`{"a": 1}`)))
+	assert.NoError(t, err)
+
+	_, err = client.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a": 1}`, captured)
+	assert.Equal(t, `{"a": 1}`, sent)
+	mockHTTPClient.AssertExpectations(t)
+}
+
+func TestNewClient_WithHTTPClient(t *testing.T) {
+	mockHTTPClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithHTTPClient(mockHTTPClient))
+
+	mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Authorization") == "Basic "+base64.StdEncoding.EncodeToString([]byte("test-token|"))
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "template-123"}}`)),
+	}, nil).Once()
+
+	_, err := client.GetBadgeTemplate("template-123")
+
+	assert.NoError(t, err)
+	mockHTTPClient.AssertExpectations(t)
+}
+
+func TestMissingOrganizationId(t *testing.T) {
+	mockHTTPClient := new(MockHTTPClient)
+	client := NewClient("test-token", "", WithHTTPClient(mockHTTPClient))
+
+	_, err := client.GetBadges("test@example.com", nil)
+
+	assert.ErrorIs(t, err, ErrMissingOrganizationId)
+	assert.ErrorContains(t, err, "[credly.GetBadges]")
+	mockHTTPClient.AssertNotCalled(t, "Do", mock.Anything)
+
+	// Public endpoints don't need an organization
+	mockHTTPClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"issuedOn": "2024-01-15T10:00:00Z"}`)),
+	}, nil).Once()
+
+	_, err = client.VerifyBadge("public-123")
+
+	assert.NoError(t, err)
+	mockHTTPClient.AssertExpectations(t)
+}
+
+func TestWithHTTP2(t *testing.T) {
+	client := NewClient("test-token", "org-123", WithHTTP2(false))
+
+	transport := client.HTTPClient.(*http.Client).Transport.(*http.Transport)
+	assert.False(t, transport.ForceAttemptHTTP2)
+	assert.NotNil(t, transport.TLSNextProto)
+	assert.Empty(t, transport.TLSNextProto)
+	assert.NotSame(t, http.DefaultTransport, transport)
+
+	client = NewClient("test-token", "org-123", WithHTTP2(true))
+
+	transport = client.HTTPClient.(*http.Client).Transport.(*http.Transport)
+	assert.True(t, transport.ForceAttemptHTTP2)
+	assert.Nil(t, transport.TLSNextProto)
+}
+
+func TestWithHTTP2_CustomHTTPClient(t *testing.T) {
+	mockHTTPClient := new(MockHTTPClient)
+
+	// Options leave non-*http.Client implementations untouched
+	client := NewClient("test-token", "org-123", WithHTTP2(false), WithHTTPClient(mockHTTPClient))
+
+	assert.Equal(t, mockHTTPClient, client.HTTPClient)
+}
+
+func TestWithHTTP2_SharedTransport(t *testing.T) {
+	shared := &http.Transport{ForceAttemptHTTP2: true}
+	custom := &http.Client{Transport: shared}
+
+	// The option applies whatever its position, to a copy of the caller's transport
+	for _, client := range []*Client{
+		NewClient("test-token", "org-123", WithHTTP2(false), WithTransport(shared)),
+		NewClient("test-token", "org-123", WithHTTP2(false), WithHTTPClient(custom)),
+	} {
+		transport := client.HTTPClient.(*http.Client).Transport.(*http.Transport)
+		assert.NotSame(t, shared, transport)
+		assert.False(t, transport.ForceAttemptHTTP2)
+		assert.NotNil(t, transport.TLSNextProto)
+	}
+
+	assert.True(t, shared.ForceAttemptHTTP2)
+	assert.Same(t, shared, custom.Transport)
+}
+
+func TestWithInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"id": "template-123"}}`))
+	}))
+	defer server.Close()
+
+	// The self-signed certificate is rejected by default
+	client := NewClient("test-token", "org-123", WithBaseURL(server.URL))
+
+	_, err := client.GetBadgeTemplate("template-123")
+	assert.ErrorContains(t, err, "certificate")
+
+	client = NewClient("test-token", "org-123", WithBaseURL(server.URL), WithInsecureSkipVerify())
+
+	template, err := client.GetBadgeTemplate("template-123")
+	assert.NoError(t, err)
+	assert.Equal(t, "template-123", template.Id)
+	assert.False(t, http.DefaultTransport.(*http.Transport).TLSClientConfig != nil &&
+		http.DefaultTransport.(*http.Transport).TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestWithInsecureSkipVerify_SharedTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"id": "template-123"}}`))
+	}))
+	defer server.Close()
+
+	// The option applies whatever its position, to a copy of the caller's transport
+	shared := &http.Transport{}
+	client := NewClient("test-token", "org-123", WithBaseURL(server.URL), WithInsecureSkipVerify(), WithTransport(shared))
+
+	_, err := client.GetBadgeTemplate("template-123")
+	assert.NoError(t, err)
+	assert.False(t, shared.TLSClientConfig != nil && shared.TLSClientConfig.InsecureSkipVerify)
+
+	custom := &http.Client{Transport: shared, Timeout: time.Minute}
+	client = NewClient("test-token", "org-123", WithBaseURL(server.URL), WithInsecureSkipVerify(), WithHTTPClient(custom))
+
+	_, err = client.GetBadgeTemplate("template-123")
+	assert.NoError(t, err)
+	assert.Same(t, shared, custom.Transport)
+	assert.False(t, shared.TLSClientConfig != nil && shared.TLSClientConfig.InsecureSkipVerify)
+	assert.Equal(t, time.Minute, client.HTTPClient.(*http.Client).Timeout)
+}
+
+func TestEnv(t *testing.T) {
+	mockHTTPClient := new(MockHTTPClient)
+	client := NewClient("prod-token", "prod-org",
+		WithEnvironment("sandbox", Environment{
+			Token:          "sandbox-token",
+			OrganizationId: "sandbox-org",
+			BaseURL:        "https://sandbox-api.credly.com/v1",
+		}),
+		WithEnvironment("production", Environment{
+			Token:          "prod-token",
+			OrganizationId: "prod-org",
+		}),
+	)
+	client.HTTPClient = mockHTTPClient
+
+	for _, tt := range []struct {
+		env   string
+		url   string
+		token string
+	}{
+		{env: "sandbox", url: "https://sandbox-api.credly.com/v1/organizations/sandbox-org/badge_templates", token: "sandbox-token"},
+		{env: "production", url: "https://api.credly.com/v1/organizations/prod-org/badge_templates", token: "prod-token"},
+	} {
+		expectedAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(tt.token+"|"))
+		mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.String() == tt.url && req.Header.Get("Authorization") == expectedAuth
+		})).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"data": []}`)),
+		}, nil).Once()
+
+		envClient, err := client.Env(tt.env)
+		assert.NoError(t, err)
+
+		_, err = envClient.GetBadgeTemplates()
+		assert.NoError(t, err)
+	}
+
+	// The parent client is left untouched
+	assert.Equal(t, "prod-org", client.OrganizationId)
+	mockHTTPClient.AssertExpectations(t)
+}
+
+func TestEnv_Unknown(t *testing.T) {
+	client := NewClient("test-token", "org-123")
+
+	envClient, err := client.Env("staging")
+
+	assert.ErrorIs(t, err, ErrUnknownEnvironment)
+	assert.Nil(t, envClient)
+}
+
+func TestWithBaseURL(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "POST":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"data": {"id": "badge-123"}}`))
+		case strings.HasSuffix(r.URL.Path, "/badge_templates/template-123"):
+			_, _ = w.Write([]byte(`{"data": {"id": "template-123"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "org-123", WithBaseURL(server.URL+"/v1/"))
+
+	_, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+	assert.NoError(t, err)
+	_, err = client.GetBadges("test@example.com", nil)
+	assert.NoError(t, err)
+	_, err = client.GetBadge("test@example.com", "template-123")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = client.GetBadgeTemplate("template-123")
+	assert.NoError(t, err)
+	_, err = client.GetBadgeTemplates()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"POST /v1/organizations/org-123/badges",
+		"GET /v1/organizations/org-123/badges",
+		"GET /v1/organizations/org-123/badges",
+		"GET /v1/organizations/org-123/badge_templates/template-123",
+		"GET /v1/organizations/org-123/badge_templates",
+	}, paths)
+}
+
+func TestNewClient_DefaultBaseURL(t *testing.T) {
+	client := NewClient("test-token", "org-123")
+
+	assert.Equal(t, DefaultBaseURL, client.BaseURL)
+	assert.Equal(t, DefaultBaseURL, (&Client{OrganizationId: "org-123"}).baseURL())
+}
+
+func TestWithUserAgent(t *testing.T) {
+	mockHTTPClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithUserAgent("badges-service/1.2"))
+	client.HTTPClient = mockHTTPClient
+
+	mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("User-Agent") == "badges-service/1.2"
+	})).Return(&http.Response{StatusCode: http.StatusOK}, nil)
+
+	req, err := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
+	assert.NoError(t, err)
+
+	_, err = client.Do(req)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(DefaultUserAgent, "credly-go"))
+	mockHTTPClient.AssertExpectations(t)
+}
+
+func TestWithTimeout(t *testing.T) {
+	client := NewClient("test-token", "org-123")
+	assert.Equal(t, 30*time.Second, client.HTTPClient.(*http.Client).Timeout)
+
+	client = NewClient("test-token", "org-123", WithTimeout(5*time.Second))
+	assert.Equal(t, 5*time.Second, client.HTTPClient.(*http.Client).Timeout)
+
+	// The timeout of a custom HTTP client is respected, whatever the order of the options
+	custom := &http.Client{Timeout: time.Minute}
+	client = NewClient("test-token", "org-123", WithTimeout(5*time.Second), WithHTTPClient(custom))
+	assert.Same(t, custom, client.HTTPClient)
+	assert.Equal(t, time.Minute, custom.Timeout)
+
+	noTimeout := &http.Client{}
+	NewClient("test-token", "org-123", WithHTTPClient(noTimeout))
+	assert.Zero(t, noTimeout.Timeout)
+
+	// The HTTP client created by NewClient keeps its timeout with a custom transport
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) { return nil, errors.New("unused") })
+	client = NewClient("test-token", "org-123", WithTransport(rt))
+	assert.Equal(t, 30*time.Second, client.HTTPClient.(*http.Client).Timeout)
+
+	client = NewClient("test-token", "org-123", WithTimeout(0), WithTransport(rt))
+	assert.Zero(t, client.HTTPClient.(*http.Client).Timeout)
+}
+
+// roundTripperFunc adapts a function to the http.RoundTripper interface.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	var authorization string
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		authorization = req.Header.Get("Authorization")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "template-123"}}`)),
+		}, nil
+	})
+
+	// Both settings apply to the HTTP client, whatever the order of the options
+	for _, opts := range [][]Option{
+		{WithTransport(rt), WithTimeout(5 * time.Second)},
+		{WithTimeout(5 * time.Second), WithTransport(rt)},
+	} {
+		client := NewClient("test-token", "org-123", opts...)
+
+		hc := client.HTTPClient.(*http.Client)
+		assert.NotNil(t, hc.Transport)
+		assert.Equal(t, 5*time.Second, hc.Timeout)
+
+		_, err := client.GetBadgeTemplate("template-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Basic "+encodeToken("test-token"), authorization)
+	}
+
+	// A custom HTTP client is left untouched, whatever the order of the options
+	mockHTTPClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithTransport(rt), WithHTTPClient(mockHTTPClient))
+	assert.Same(t, mockHTTPClient, client.HTTPClient)
+
+	custom := &http.Client{}
+	client = NewClient("test-token", "org-123", WithHTTPClient(custom), WithTransport(rt))
+	assert.Same(t, custom, client.HTTPClient)
+	assert.Nil(t, custom.Transport)
+}
+
+func TestWithDefaultHeader(t *testing.T) {
+	mockHTTPClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123",
+		WithDefaultHeader("X-Beta-Feature", "bulk-issue"),
+		WithDefaultHeader("X-Correlation-ID", "default"),
+		WithDefaultHeader("Authorization", "Bearer stolen"),
+	)
+	client.HTTPClient = mockHTTPClient
+
+	mockHTTPClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("X-Beta-Feature") == "bulk-issue" &&
+			req.Header.Get("X-Correlation-ID") == "trace-123" &&
+			req.Header.Get("Authorization") == "Basic "+client.authToken
+	})).Return(&http.Response{StatusCode: http.StatusOK}, nil)
+
+	ctx := ContextWithHeader(context.Background(), "X-Correlation-ID", "trace-123")
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.credly.com/v1/some-endpoint", nil)
+	assert.NoError(t, err)
+
+	_, err = client.Do(req)
+
+	assert.NoError(t, err)
+	mockHTTPClient.AssertExpectations(t)
+}
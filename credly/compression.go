@@ -0,0 +1,85 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// decompressResponse replaces the body of a gzip or deflate encoded response
+// with a decompressing reader. Responses already decompressed by the transport
+// carry no Content-Encoding and are returned as is.
+//
+// Failures to decompress the body are reported as *DecompressionError, rather
+// than surfacing as confusing errors from the JSON decoder.
+func decompressResponse(resp *http.Response) (*http.Response, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	var r io.ReadCloser
+	var err error
+	switch encoding {
+	case "gzip", "x-gzip":
+		r, err = gzip.NewReader(resp.Body)
+	case "deflate":
+		r, err = zlib.NewReader(resp.Body)
+	default:
+		return resp, nil
+	}
+
+	if errors.Is(err, io.EOF) {
+		// Empty body
+		return resp, nil
+	}
+	if err != nil {
+		resp.Body.Close()
+		return nil, &DecompressionError{ContentEncoding: encoding, Err: err}
+	}
+
+	resp.Body = &decompressedBody{r: r, body: resp.Body, encoding: encoding}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return resp, nil
+}
+
+// decompressedBody reads a decompressed response body, reporting stream
+// corruption as *DecompressionError.
+type decompressedBody struct {
+	r        io.ReadCloser
+	body     io.ReadCloser
+	encoding string
+}
+
+// Read implements the io.Reader interface.
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = &DecompressionError{ContentEncoding: b.encoding, Err: err}
+	}
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (b *decompressedBody) Close() error {
+	b.r.Close()
+	return b.body.Close()
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func gzipped(s string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write([]byte(s))
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+func TestWithCompression(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithCompression())
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Accept-Encoding") == "gzip, deflate"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:       io.NopCloser(bytes.NewReader(gzipped(`{"data": [{"id": "template-123"}]}`))),
+	}, nil)
+
+	templates, err := client.GetBadgeTemplates()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeTemplate{{Id: "template-123"}}, templates)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDecompressResponse_CorruptHeader(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": []}`)),
+	}, nil)
+
+	templates, err := client.GetBadgeTemplates()
+
+	var decompressErr *DecompressionError
+	assert.ErrorAs(t, err, &decompressErr)
+	assert.Equal(t, "gzip", decompressErr.ContentEncoding)
+	assert.Contains(t, err.Error(), "Failed to decompress response (Content-Encoding: gzip)")
+	assert.Empty(t, templates)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDecompressResponse_CorruptStream(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	// Valid gzip header, but the stream is cut short
+	body := gzipped(`{"data": [{"id": "template-123"}, {"id": "template-456"}]}`)
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:       io.NopCloser(bytes.NewReader(body[:len(body)/2])),
+	}, nil)
+
+	_, err := client.GetBadgeTemplates()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to decompress response (Content-Encoding: gzip)")
+	mockClient.AssertExpectations(t)
+}
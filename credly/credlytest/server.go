@@ -0,0 +1,380 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package credlytest provides a fake Credly API server for testing code using
+// the credly package, in the spirit of net/http/httptest.
+//
+// The server holds badge templates and badges in memory. It serves the
+// endpoints used to read templates and badges, issue badges and change their
+// state, and records the badges issued through it. Badge listings support the
+// filter terms sent for a credly.BadgeFilter, and respond 400 to other terms as
+// Credly does:
+//
+//	srv := credlytest.NewServer("org-123")
+//	defer srv.Close()
+//	srv.AddTemplate(credly.BadgeTemplate{Id: "template-123", Name: "Cilium Associate"})
+//
+//	client := srv.Client()
+//	badge, err := client.IssueBadge("template-123", "jane@example.com", "Jane", "Doe")
+package credlytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isovalent/credly-go/credly"
+)
+
+// timeLayout is the layout of the timestamps sent by credly.Client.
+const timeLayout = "2006-01-02 15:04:05 -0700"
+
+// Server is a fake Credly API server for a single organization.
+type Server struct {
+	*httptest.Server
+
+	// OrganizationId is the ID of the organization served.
+	OrganizationId string
+
+	mu        sync.Mutex
+	templates []credly.BadgeTemplate
+	badges    []credly.BadgeInfo
+	issued    []credly.BadgeInfo
+	nextId    int
+}
+
+// NewServer starts a fake Credly API server for the organization. The caller
+// should call Close when finished, to shut it down.
+func NewServer(organizationId string) *Server {
+	s := &Server{OrganizationId: organizationId}
+
+	mux := http.NewServeMux()
+	prefix := "/organizations/" + organizationId
+	mux.HandleFunc("GET "+prefix+"/badge_templates", s.listTemplates)
+	mux.HandleFunc("GET "+prefix+"/badge_templates/{id}", s.getTemplate)
+	mux.HandleFunc("GET "+prefix+"/badges", s.listBadges)
+	mux.HandleFunc("POST "+prefix+"/badges", s.issueBadge)
+	mux.HandleFunc("GET "+prefix+"/badges/{id}", s.getBadge)
+	mux.HandleFunc("PUT "+prefix+"/badges/{id}/revoke", s.setState(credly.BadgeStateRevoked))
+	mux.HandleFunc("PUT "+prefix+"/badges/{id}/accept", s.setState(credly.BadgeStateAccepted))
+	mux.HandleFunc("PUT "+prefix+"/badges/{id}/reject", s.setState(credly.BadgeStateRejected))
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Client returns a credly.Client for the organization, sending its requests
+// to the server. Options are applied after the ones pointing the client to
+// the server.
+func (s *Server) Client(opts ...credly.Option) *credly.Client {
+	opts = append([]credly.Option{credly.WithBaseURL(s.URL), credly.WithHTTPClient(s.Server.Client())}, opts...)
+	return credly.NewClient("credlytest-token", s.OrganizationId, opts...)
+}
+
+// AddTemplate adds a badge template to the server.
+func (s *Server) AddTemplate(t credly.BadgeTemplate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates = append(s.templates, t)
+}
+
+// AddBadge adds an already issued badge to the server. It isn't reported by
+// IssuedBadges. An ID is assigned if b.Id is empty.
+func (s *Server) AddBadge(b credly.BadgeInfo) credly.BadgeInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if b.Id == "" {
+		b.Id = s.newId()
+	}
+	s.badges = append(s.badges, b)
+	return b
+}
+
+// IssuedBadges returns the badges issued through the server, in order.
+func (s *Server) IssuedBadges() []credly.BadgeInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]credly.BadgeInfo(nil), s.issued...)
+}
+
+// newId returns a new badge ID. s.mu must be held.
+func (s *Server) newId() string {
+	s.nextId++
+	return fmt.Sprintf("badge-%d", s.nextId)
+}
+
+// findTemplate returns the template with the given ID. s.mu must be held.
+func (s *Server) findTemplate(id string) (credly.BadgeTemplate, bool) {
+	for _, t := range s.templates {
+		if t.Id == id {
+			return t, true
+		}
+	}
+	return credly.BadgeTemplate{}, false
+}
+
+func (s *Server) listTemplates(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	templates := append([]credly.BadgeTemplate{}, s.templates...)
+	s.mu.Unlock()
+
+	writeList(w, templates)
+}
+
+func (s *Server) getTemplate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	t, ok := s.findTemplate(r.PathValue("id"))
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Badge template not found")
+		return
+	}
+	writeData(w, http.StatusOK, t)
+}
+
+func (s *Server) listBadges(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	badges := []credly.BadgeInfo{}
+	for _, b := range s.badges {
+		if filter.matches(b) {
+			badges = append(badges, b)
+		}
+	}
+	s.mu.Unlock()
+
+	writeList(w, badges)
+}
+
+func (s *Server) getBadge(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, b := range s.badges {
+		if b.Id == r.PathValue("id") {
+			writeData(w, http.StatusOK, b)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Badge not found")
+}
+
+// issueParams holds the parameters of a badge issuance request.
+type issueParams struct {
+	TemplateId   string            `json:"badge_template_id"`
+	Email        string            `json:"recipient_email"`
+	FirstName    string            `json:"issued_to_first_name"`
+	LastName     string            `json:"issued_to_last_name"`
+	IssuedAt     string            `json:"issued_at"`
+	ExpiresAt    string            `json:"expires_at"`
+	CustomFields map[string]string `json:"custom_fields"`
+}
+
+func (s *Server) issueBadge(w http.ResponseWriter, r *http.Request) {
+	var p issueParams
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.findTemplate(p.TemplateId)
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Badge template not found")
+		return
+	}
+	for _, b := range s.badges {
+		if b.Template.Id == p.TemplateId && strings.EqualFold(b.User.Email, p.Email) && b.State != credly.BadgeStateRevoked {
+			writeError(w, http.StatusUnprocessableEntity, "User already has this badge")
+			return
+		}
+	}
+
+	b := credly.BadgeInfo{
+		Id:           s.newId(),
+		State:        credly.BadgeStatePending,
+		CustomFields: p.CustomFields,
+	}
+	b.IssuedAt, _ = time.Parse(timeLayout, p.IssuedAt)
+	b.ExpiresAt, _ = time.Parse(timeLayout, p.ExpiresAt)
+	b.UpdatedAt = time.Now()
+	b.Image.Url = t.ImageUrl
+	b.Template = t
+	b.Issuer.Id = s.OrganizationId
+	b.User.Email = p.Email
+	b.User.FirstName = p.FirstName
+	b.User.LastName = p.LastName
+
+	s.badges = append(s.badges, b)
+	s.issued = append(s.issued, b)
+
+	writeData(w, http.StatusCreated, b)
+}
+
+// setState returns a handler moving a badge to state.
+func (s *Server) setState(state string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var params struct {
+			Reason string `json:"reason"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&params)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for i, b := range s.badges {
+			if b.Id != r.PathValue("id") {
+				continue
+			}
+			if b.State == state || b.State == credly.BadgeStateRevoked {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Badge is %s", b.State))
+				return
+			}
+			b.State = state
+			b.UpdatedAt = time.Now()
+			switch state {
+			case credly.BadgeStateAccepted:
+				b.AcceptedAt = b.UpdatedAt
+			case credly.BadgeStateRevoked:
+				b.RevokedAt = b.UpdatedAt
+				b.RevocationReason = params.Reason
+			}
+			s.badges[i] = b
+			writeData(w, http.StatusOK, b)
+			return
+		}
+		writeError(w, http.StatusNotFound, "Badge not found")
+	}
+}
+
+// badgeFilter holds the terms of a badge filter query parameter. Unset
+// strings, slices and times don't filter.
+type badgeFilter struct {
+	email, templateId, state string
+	tags                     []string
+	issuedMin, issuedMax     time.Time
+	updatedMin, updatedMax   time.Time
+}
+
+// parseFilter parses a filter query parameter. It returns an error for terms
+// the server doesn't support, or with an invalid value.
+func parseFilter(filter string) (badgeFilter, error) {
+	var f badgeFilter
+	for _, term := range strings.Split(filter, "|") {
+		name, value, ok := strings.Cut(term, "::")
+		if !ok {
+			continue
+		}
+
+		var err error
+		switch name {
+		case "recipient_email_all":
+			f.email = value
+		case "badge_template_id":
+			f.templateId = value
+		case "state":
+			f.state = value
+		case "badge_templates[reporting_tags]":
+			f.tags = strings.Split(value, ",")
+		case "issued_at_min":
+			f.issuedMin, err = time.Parse(time.RFC3339, value)
+		case "issued_at_max":
+			f.issuedMax, err = time.Parse(time.RFC3339, value)
+		case "updated_at_min":
+			f.updatedMin, err = time.Parse(time.RFC3339, value)
+		case "updated_at_max":
+			f.updatedMax, err = time.Parse(time.RFC3339, value)
+		default:
+			return f, fmt.Errorf("Unknown filter: %q", name)
+		}
+		if err != nil {
+			return f, fmt.Errorf("Invalid %s: %q", name, value)
+		}
+	}
+	return f, nil
+}
+
+// matches reports whether a badge matches the filter. Time bounds are inclusive.
+func (f badgeFilter) matches(b credly.BadgeInfo) bool {
+	if f.email != "" && !strings.EqualFold(b.User.Email, f.email) {
+		return false
+	}
+	if f.templateId != "" && b.Template.Id != f.templateId {
+		return false
+	}
+	if f.state != "" && b.State != f.state {
+		return false
+	}
+	if f.tags != nil && !hasAnyTag(b.Template.ReportingTags, f.tags) {
+		return false
+	}
+	return inRange(b.IssuedAt, f.issuedMin, f.issuedMax) && inRange(b.UpdatedAt, f.updatedMin, f.updatedMax)
+}
+
+// hasAnyTag reports whether tags contains any of want.
+func hasAnyTag(tags, want []string) bool {
+	for _, w := range want {
+		for _, t := range tags {
+			if t == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inRange reports whether t is within [min, max], a zero bound being unset.
+func inRange(t, min, max time.Time) bool {
+	return (min.IsZero() || !t.Before(min)) && (max.IsZero() || !t.After(max))
+}
+
+func writeList[T any](w http.ResponseWriter, items []T) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": items,
+		"metadata": credly.PageMetadata{
+			Count:       len(items),
+			CurrentPage: 1,
+			TotalCount:  len(items),
+			TotalPages:  1,
+			PerPage:     len(items),
+		},
+	})
+}
+
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, map[string]interface{}{"data": data})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"message": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credlytest
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/isovalent/credly-go/credly"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	srv := NewServer("org-123")
+	defer srv.Close()
+
+	srv.AddTemplate(credly.BadgeTemplate{Id: "template-123", Name: "Cilium Associate"})
+	existing := srv.AddBadge(credly.BadgeInfo{State: credly.BadgeStateAccepted})
+	assert.NotEmpty(t, existing.Id)
+
+	client := srv.Client()
+
+	template, err := client.GetBadgeTemplate("template-123")
+	assert.NoError(t, err)
+	assert.Equal(t, "Cilium Associate", template.Name)
+
+	_, err = client.GetBadgeTemplate("unknown")
+	assert.ErrorIs(t, err, credly.ErrNotFound)
+
+	badge, err := client.IssueBadge("template-123", "jane@example.com", "Jane", "Doe")
+	assert.NoError(t, err)
+	assert.Equal(t, credly.BadgeStatePending, badge.State)
+	assert.Equal(t, "template-123", badge.Template.Id)
+
+	_, err = client.IssueBadge("template-123", "jane@example.com", "Jane", "Doe")
+	assert.ErrorIs(t, err, credly.ErrAlreadyIssued)
+
+	_, err = client.IssueBadge("unknown", "jane@example.com", "Jane", "Doe")
+	assert.ErrorIs(t, err, credly.ErrValidation)
+
+	issued := srv.IssuedBadges()
+	assert.Len(t, issued, 1)
+	assert.Equal(t, "jane@example.com", issued[0].User.Email)
+
+	found, err := client.GetBadge("jane@example.com", "template-123")
+	assert.NoError(t, err)
+	assert.Equal(t, badge.Id, found.Id)
+
+	badges, err := client.GetBadges("john@example.com", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, badges)
+
+	revoked, err := client.RevokeBadge(badge.Id, "Exam voided")
+	assert.NoError(t, err)
+	assert.Equal(t, credly.BadgeStateRevoked, revoked.State)
+	assert.Equal(t, "Exam voided", revoked.RevocationReason)
+
+	byId, err := client.GetBadgeById(badge.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, credly.BadgeStateRevoked, byId.State)
+}
+
+func TestServer_Filter(t *testing.T) {
+	srv := NewServer("org-123")
+	defer srv.Close()
+
+	jan := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	old := srv.AddBadge(credly.BadgeInfo{
+		IssuedAt:  jan,
+		UpdatedAt: jan,
+		Template:  credly.BadgeTemplate{Id: "template-123", ReportingTags: []string{"networking"}},
+	})
+	recent := srv.AddBadge(credly.BadgeInfo{
+		IssuedAt:  jun,
+		UpdatedAt: jun,
+		Template:  credly.BadgeTemplate{Id: "template-456", ReportingTags: []string{"security"}},
+	})
+
+	client := srv.Client()
+
+	badges, err := client.GetBadgesByFilter(credly.BadgeFilter{Tags: []string{"security", "ebpf"}})
+	assert.NoError(t, err)
+	assert.Len(t, badges, 1)
+	assert.Equal(t, recent.Id, badges[0].Id)
+
+	// Time bounds are inclusive
+	badges, err = client.GetBadgesByFilter(credly.BadgeFilter{IssuedAfter: jan, IssuedBefore: jan})
+	assert.NoError(t, err)
+	assert.Len(t, badges, 1)
+	assert.Equal(t, old.Id, badges[0].Id)
+
+	badges, err = client.GetBadgesByFilter(credly.BadgeFilter{UpdatedAfter: jan.Add(time.Hour)})
+	assert.NoError(t, err)
+	assert.Len(t, badges, 1)
+	assert.Equal(t, recent.Id, badges[0].Id)
+
+	badges, err = client.GetBadgesByFilter(credly.BadgeFilter{UpdatedBefore: jan.Add(-time.Hour)})
+	assert.NoError(t, err)
+	assert.Empty(t, badges)
+}
+
+func TestServer_BadgesModifiedBetween(t *testing.T) {
+	srv := NewServer("org-123")
+	defer srv.Close()
+
+	q1 := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	q2 := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	q3 := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	// Issued during the range but accepted after it
+	issued := srv.AddBadge(credly.BadgeInfo{IssuedAt: q2, UpdatedAt: q3, State: credly.BadgeStateAccepted})
+	// Issued before the range and revoked during it
+	revoked := srv.AddBadge(credly.BadgeInfo{IssuedAt: q1, RevokedAt: q2.Add(time.Hour), UpdatedAt: q2.Add(time.Hour), State: credly.BadgeStateRevoked})
+	// Issued before the range and revoked after it
+	srv.AddBadge(credly.BadgeInfo{IssuedAt: q1, RevokedAt: q3, UpdatedAt: q3, State: credly.BadgeStateRevoked})
+	// Issued before the range and updated after it
+	srv.AddBadge(credly.BadgeInfo{IssuedAt: q1, UpdatedAt: q3, State: credly.BadgeStateAccepted})
+
+	badges, err := srv.Client().GetBadgesModifiedBetween(q2, q3.Add(-time.Second))
+	assert.NoError(t, err)
+	if assert.Len(t, badges, 2) {
+		assert.Equal(t, issued.Id, badges[0].Id)
+		assert.Equal(t, revoked.Id, badges[1].Id)
+	}
+}
+
+func TestServer_UnknownFilter(t *testing.T) {
+	srv := NewServer("org-123")
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/organizations/org-123/badges?filter=" + url.QueryEscape("expires_at_min::2024-01-01T00:00:00Z"))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
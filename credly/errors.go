@@ -0,0 +1,474 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// requestIdHeader is the response header carrying Credly's request/correlation ID.
+const requestIdHeader = "X-Request-Id"
+
+// LastRequestId returns the request ID Credly assigned to the most recent
+// response carrying one, successful or not, e.g. to log alongside the result
+// of a call that succeeded but returned unexpected data. It returns false if no
+// such response was received yet, or if the Client wasn't created with
+// NewClient. Failed calls also report it in APIError.RequestId.
+//
+// Clients returned by Env track their request ID separately from their parent.
+func (c *Client) LastRequestId() (string, bool) {
+	if c.requestId == nil {
+		return "", false
+	}
+
+	c.requestId.mu.Lock()
+	defer c.requestId.mu.Unlock()
+	return c.requestId.id, c.requestId.id != ""
+}
+
+// requestIdTracker holds the last request ID reported by Credly.
+type requestIdTracker struct {
+	mu sync.Mutex
+	id string
+}
+
+// observe records the request ID in the headers of resp, if any.
+func (t *requestIdTracker) observe(resp *http.Response) {
+	if t == nil {
+		return
+	}
+
+	id := resp.Header.Get(requestIdHeader)
+	if id == "" {
+		return
+	}
+
+	t.mu.Lock()
+	t.id = id
+	t.mu.Unlock()
+}
+
+// APIError is returned when the Credly API responds with an unexpected status code.
+type APIError struct {
+	// Op is the client operation that failed, e.g. "credly.GetBadges".
+	Op string
+
+	// StatusCode is the HTTP status code returned by Credly.
+	StatusCode int
+
+	// RequestId is the request ID Credly assigned to the call, if any.
+	// Credly support asks for it when investigating failed requests.
+	RequestId string
+
+	// RetryAfter is the delay requested by Credly through the Retry-After
+	// header before trying again, or zero if none was given.
+	RetryAfter time.Duration
+
+	// Message describes the failure, as returned by Credly in the response body if any.
+	Message string
+
+	// Errors lists the individual error messages returned by Credly, e.g. one
+	// per invalid parameter.
+	Errors []string
+
+	// FieldErrors lists the errors of Errors that Credly attributed to a
+	// request parameter, e.g. "recipient_email".
+	FieldErrors []FieldError
+
+	// kind is the sentinel error this error matches, overriding the one
+	// derived from the status code.
+	kind error
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("[%s] API request failed with status code: %d", e.Op, e.StatusCode)
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	if e.RequestId != "" {
+		msg = fmt.Sprintf("%s (request id: %s)", msg, e.RequestId)
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel error matching the failure, if any, so that
+// callers can check for it with errors.Is, e.g. errors.Is(err, ErrNotFound).
+func (e *APIError) Unwrap() error {
+	if e.kind != nil {
+		return e.kind
+	}
+
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnprocessableEntity:
+		return ErrValidation
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrServiceUnavailable
+	}
+	return nil
+}
+
+// alreadyIssuedError is returned by IssueBadge when the recipient already has
+// the badge. Its message is the one from Credly alone, ErrBadgeAlreadyIssued
+// by default, as callers may compare it with the error string.
+type alreadyIssuedError struct {
+	*APIError
+}
+
+// Error implements the error interface.
+func (e *alreadyIssuedError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying APIError.
+func (e *alreadyIssuedError) Unwrap() error {
+	return e.APIError
+}
+
+// isBadgeAlreadyIssued reports whether err indicates that the recipient already has the badge.
+func isBadgeAlreadyIssued(err error) bool {
+	return errors.Is(err, ErrAlreadyIssued)
+}
+
+// newAPIError builds an APIError for the given operation from an HTTP response.
+// The response body is read to extract the error details sent by Credly.
+func newAPIError(op string, resp *http.Response) *APIError {
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	}
+
+	return newAPIErrorFromBody(op, resp, body)
+}
+
+// newAPIErrorFromBody builds an APIError for the given operation from an HTTP
+// response whose body was already read.
+func newAPIErrorFromBody(op string, resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		Op:         op,
+		StatusCode: resp.StatusCode,
+		RequestId:  resp.Header.Get(requestIdHeader),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+	e.Message, e.Errors, e.FieldErrors = parseErrorBody(body)
+
+	return e
+}
+
+// FieldError is an error Credly attributed to a request parameter.
+type FieldError struct {
+	// Field is the name of the parameter, e.g. "recipient_email".
+	Field string
+
+	// Message describes the error, e.g. "is invalid".
+	Message string
+}
+
+// maxErrorBodySize bounds how much of an error response body is read.
+const maxErrorBodySize = 64 << 10
+
+// errorBody represents the body of a Credly error response. The message and
+// the errors may be sent at the top level or inside the data member.
+type errorBody struct {
+	Message string            `json:"message"`
+	Errors  []json.RawMessage `json:"errors"`
+
+	Data struct {
+		Message string            `json:"message"`
+		Errors  []json.RawMessage `json:"errors"`
+	} `json:"data"`
+}
+
+// parseErrorBody extracts the error message, the individual error messages and
+// the errors attributed to request parameters from the body of a Credly error
+// response. It returns empty values if the body isn't a JSON error.
+func parseErrorBody(body []byte) (message string, errs []string, fields []FieldError) {
+	var eb errorBody
+	if err := json.Unmarshal(body, &eb); err != nil {
+		// Not a Credly error, e.g. an HTML page from a gateway during an outage
+		return bodySnippet(body), nil, nil
+	}
+
+	for _, raw := range append(eb.Errors, eb.Data.Errors...) {
+		msg, field := parseErrorItem(raw)
+		if msg == "" {
+			continue
+		}
+		errs = append(errs, msg)
+		if field != "" {
+			fields = append(fields, FieldError{Field: field, Message: msg})
+		}
+	}
+
+	message = eb.Message
+	if message == "" {
+		message = eb.Data.Message
+	}
+	if message == "" {
+		message = strings.Join(errs, "; ")
+	}
+
+	return message, errs, fields
+}
+
+// maxBodySnippetSize bounds the length of the response body snippets included in errors.
+const maxBodySnippetSize = 200
+
+// bodySnippet returns the beginning of a response body that couldn't be parsed,
+// with whitespace collapsed, so that errors give some context about it.
+func bodySnippet(body []byte) string {
+	s := strings.Join(strings.Fields(strings.ToValidUTF8(string(body), "\uFFFD")), " ")
+	if len(s) <= maxBodySnippetSize {
+		return s
+	}
+
+	cut := maxBodySnippetSize
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
+
+// parseErrorItem returns the message of an item of the errors array, which
+// Credly sends either as a plain string or as an object, and the parameter it
+// is attributed to, if any. Objects follow either Credly's own shape, with an
+// attribute member, or the JSON:API one, with a source member.
+func parseErrorItem(raw json.RawMessage) (message, field string) {
+	var msg string
+	if err := json.Unmarshal(raw, &msg); err == nil {
+		return msg, ""
+	}
+
+	var item struct {
+		Message   string `json:"message"`
+		Detail    string `json:"detail"`
+		Title     string `json:"title"`
+		Attribute string `json:"attribute"`
+		Source    struct {
+			Pointer   string `json:"pointer"`
+			Parameter string `json:"parameter"`
+		} `json:"source"`
+	}
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return "", ""
+	}
+
+	switch {
+	case item.Message != "":
+		message = item.Message
+	case item.Detail != "":
+		message = item.Detail
+	default:
+		message = item.Title
+	}
+
+	switch {
+	case item.Attribute != "":
+		field = item.Attribute
+	case item.Source.Pointer != "":
+		field = path.Base(item.Source.Pointer)
+	default:
+		field = item.Source.Parameter
+	}
+
+	return message, field
+}
+
+// parseRetryAfter parses the value of a Retry-After header, given either as a
+// number of seconds or as an HTTP date. It returns zero if the value is empty,
+// invalid or in the past.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
+
+// ItemDecodeError describes an item of a list response that could not be decoded.
+type ItemDecodeError struct {
+	// Index is the position of the item in the response page.
+	Index int
+
+	// Raw is the undecoded JSON of the item.
+	Raw json.RawMessage
+
+	// Err is the decoding error.
+	Err error
+}
+
+// PartialDecodeError is returned in lenient decoding mode when some items of a
+// list response could not be decoded. The items that decoded successfully are
+// returned alongside it.
+type PartialDecodeError struct {
+	// Op is the client operation that returned the response, e.g. "credly.GetBadges".
+	Op string
+
+	// Items lists the items that failed to decode.
+	Items []ItemDecodeError
+}
+
+// Error implements the error interface.
+func (e *PartialDecodeError) Error() string {
+	if len(e.Items) == 0 {
+		return fmt.Sprintf("[%s] Failed to parse JSON data", e.Op)
+	}
+	return fmt.Sprintf("[%s] Failed to parse %d item(s) in JSON data: %v", e.Op, len(e.Items), e.Items[0].Err)
+}
+
+// DecompressionError is returned when a compressed response body can't be
+// decompressed, e.g. because a proxy corrupted or double-compressed it.
+type DecompressionError struct {
+	// ContentEncoding is the Content-Encoding of the response.
+	ContentEncoding string
+
+	// Err is the underlying decompression error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *DecompressionError) Error() string {
+	return fmt.Sprintf("[credly] Failed to decompress response (Content-Encoding: %s): %v", e.ContentEncoding, e.Err)
+}
+
+// Unwrap returns the underlying decompression error.
+func (e *DecompressionError) Unwrap() error {
+	return e.Err
+}
+
+// ReplaceBadgeError is returned by ReplaceBadge when the old badge was revoked
+// but issuing the new one failed, leaving the recipient without a badge.
+type ReplaceBadgeError struct {
+	// OldBadgeId is the ID of the badge that was revoked.
+	OldBadgeId string
+
+	// Err is the error that made issuing the new badge fail.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ReplaceBadgeError) Error() string {
+	return fmt.Sprintf("[credly.ReplaceBadge] Badge %s was revoked but issuing its replacement failed: %v", e.OldBadgeId, e.Err)
+}
+
+// Unwrap returns the error that made issuing the new badge fail.
+func (e *ReplaceBadgeError) Unwrap() error {
+	return e.Err
+}
+
+// LookupError is returned by batch lookups, such as GetBadgesForEmails, when
+// some of the items could not be retrieved.
+type LookupError struct {
+	// Op is the client operation that failed, e.g. "credly.GetBadgesForEmails".
+	Op string
+
+	// Errors maps the keys that failed, e.g. email addresses, to the error
+	// returned for them.
+	Errors map[string]error
+}
+
+// Error implements the error interface.
+func (e *LookupError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for k := range e.Errors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		return fmt.Sprintf("[%s] Failed to retrieve items", e.Op)
+	}
+	return fmt.Sprintf("[%s] Failed to retrieve %d item(s), first %s: %v", e.Op, len(keys), keys[0], e.Errors[keys[0]])
+}
+
+// Unwrap returns the errors of the failed lookups, so callers can check for
+// them with errors.Is, e.g. errors.Is(err, ErrNotFound).
+func (e *LookupError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// BulkError is returned by BulkIssueResults.Err when issuing a badge failed
+// for some of the recipients.
+type BulkError struct {
+	// Op is the client operation that failed, e.g. "credly.IssueBadges".
+	Op string
+
+	// Failures lists the recipients for which issuance failed, in the order
+	// they were given.
+	Failures []BulkFailure
+
+	// Succeeded is the number of badges issued.
+	Succeeded int
+}
+
+// BulkFailure is the failure to issue a badge to one recipient, see BulkError.
+type BulkFailure struct {
+	// Email is the email address of the recipient.
+	Email string
+
+	// Err is the error returned for the recipient.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BulkError) Error() string {
+	if len(e.Failures) == 0 {
+		return fmt.Sprintf("[%s] Failed to issue badges, %d succeeded", e.Op, e.Succeeded)
+	}
+	return fmt.Sprintf("[%s] Failed to issue %d badge(s), %d succeeded, first %s: %v",
+		e.Op, len(e.Failures), e.Succeeded, e.Failures[0].Email, e.Failures[0].Err)
+}
+
+// Unwrap returns the errors of the failed recipients, so callers can check for
+// them with errors.Is, e.g. errors.Is(err, ErrAlreadyIssued).
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		errs = append(errs, f.Err)
+	}
+	return errs
+}
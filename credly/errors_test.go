@@ -0,0 +1,235 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIError_RequestId(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+	}
+
+	// Simulate a failure response carrying a Credly request ID
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Header:     http.Header{"X-Request-Id": []string{"req-abc-123"}},
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil)
+
+	_, err := client.GetBadgeTemplates()
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "credly.GetBadgeTemplates", apiErr.Op)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	assert.Equal(t, "req-abc-123", apiErr.RequestId)
+	assert.Equal(t, "[credly.GetBadgeTemplates] API request failed with status code: 500 (request id: req-abc-123)", err.Error())
+	mockClient.AssertExpectations(t)
+}
+
+func TestAPIError_NoRequestId(t *testing.T) {
+	err := &APIError{Op: "credly.GetBadges", StatusCode: http.StatusBadGateway}
+
+	assert.Equal(t, "[credly.GetBadges] API request failed with status code: 502", err.Error())
+}
+
+func TestLastRequestId(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithEnvironment("sandbox", Environment{Token: "sandbox-token"}))
+	client.HTTPClient = mockClient
+
+	_, ok := client.LastRequestId()
+	assert.False(t, ok)
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"X-Request-Id": []string{"req-abc-123"}},
+		Body:       io.NopCloser(strings.NewReader(`{"data": {"id": "template-123"}}`)),
+	}, nil).Once()
+
+	_, err := client.GetBadgeTemplate("template-123")
+	assert.NoError(t, err)
+
+	id, ok := client.LastRequestId()
+	assert.True(t, ok)
+	assert.Equal(t, "req-abc-123", id)
+
+	// Responses without a request ID don't clear the last one
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"data": {"id": "template-123"}}`)),
+	}, nil).Once()
+
+	_, err = client.GetBadgeTemplate("template-123")
+	assert.NoError(t, err)
+
+	id, _ = client.LastRequestId()
+	assert.Equal(t, "req-abc-123", id)
+
+	// Environments track their own request ID
+	sandbox, err := client.Env("sandbox")
+	assert.NoError(t, err)
+	_, ok = sandbox.LastRequestId()
+	assert.False(t, ok)
+	mockClient.AssertExpectations(t)
+}
+
+func TestAPIError_ServiceUnavailable(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	// Simulate a maintenance window
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Retry-After": []string{"120"}},
+		Body:       io.NopCloser(bytes.NewBufferString("<html>Down for maintenance</html>")),
+	}, nil)
+
+	_, err := client.GetBadgeTemplate("template-123")
+
+	assert.ErrorIs(t, err, ErrServiceUnavailable)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 120*time.Second, apiErr.RetryAfter)
+	mockClient.AssertExpectations(t)
+}
+
+func TestAPIError_OtherServerErrors(t *testing.T) {
+	err := &APIError{Op: "credly.GetBadges", StatusCode: http.StatusInternalServerError}
+
+	assert.NotErrorIs(t, err, ErrServiceUnavailable)
+	assert.Nil(t, err.Unwrap())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-5", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter("Sat, 01 Jun 2024 12:01:30 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Sat, 01 Jun 2024 11:00:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
+
+func TestAPIError_Sentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		err    error
+	}{
+		{status: http.StatusUnauthorized, err: ErrUnauthorized},
+		{status: http.StatusForbidden, err: ErrUnauthorized},
+		{status: http.StatusNotFound, err: ErrNotFound},
+		{status: http.StatusTooManyRequests, err: ErrRateLimited},
+		{status: http.StatusServiceUnavailable, err: ErrServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		err := newAPIError("credly.GetBadges", &http.Response{StatusCode: tt.status})
+		assert.ErrorIs(t, err, tt.err, "status %d", tt.status)
+	}
+}
+
+func TestAPIError_Message(t *testing.T) {
+	err := &APIError{Op: "credly.IssueBadge", StatusCode: 422, Message: "User already has this badge", RequestId: "req-1"}
+
+	assert.Equal(t, "[credly.IssueBadge] API request failed with status code: 422: User already has this badge (request id: req-1)", err.Error())
+}
+
+func TestParseErrorBody_NotJSON(t *testing.T) {
+	message, errs, fields := parseErrorBody([]byte("<html>\n  Bad Gateway\n</html>"))
+
+	// A snippet of the body is reported instead
+	assert.Equal(t, "<html> Bad Gateway </html>", message)
+	assert.Empty(t, errs)
+	assert.Empty(t, fields)
+
+	message, _, _ = parseErrorBody(nil)
+	assert.Empty(t, message)
+}
+
+func TestNewAPIError_HTMLBody(t *testing.T) {
+	page := "<!DOCTYPE html><html><head><title>502: Bad gateway</title></head><body>" + strings.Repeat("é", 300) + "</body></html>"
+	resp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Header:     http.Header{"Content-Type": []string{"text/html"}},
+		Body:       io.NopCloser(strings.NewReader(page)),
+	}
+
+	err := newAPIError("credly.GetBadges", resp)
+
+	assert.True(t, strings.HasPrefix(err.Message, "<!DOCTYPE html><html><head><title>502: Bad gateway</title>"))
+	assert.True(t, strings.HasSuffix(err.Message, "..."))
+	assert.True(t, utf8.ValidString(err.Message))
+	assert.LessOrEqual(t, len(err.Message), maxBodySnippetSize+len("..."))
+	assert.Contains(t, err.Error(), "status code: 502: <!DOCTYPE html>")
+}
+
+func TestParseErrorBody_FieldErrors(t *testing.T) {
+	body := `{"data": {"message": "Validation failed", "errors": [
+		{"attribute": "recipient_email", "message": "is invalid"},
+		{"source": {"pointer": "/data/attributes/issued_at"}, "detail": "is in the future"},
+		{"source": {"parameter": "badge_template_id"}, "title": "is unknown"},
+		"Something else went wrong"
+	]}}`
+
+	message, errs, fields := parseErrorBody([]byte(body))
+
+	assert.Equal(t, "Validation failed", message)
+	assert.Equal(t, []string{"is invalid", "is in the future", "is unknown", "Something else went wrong"}, errs)
+	assert.Equal(t, []FieldError{
+		{Field: "recipient_email", Message: "is invalid"},
+		{Field: "issued_at", Message: "is in the future"},
+		{Field: "badge_template_id", Message: "is unknown"},
+	}, fields)
+}
+
+func TestPartialDecodeError_NoItems(t *testing.T) {
+	err := &PartialDecodeError{Op: "credly.GetBadges"}
+
+	assert.Equal(t, "[credly.GetBadges] Failed to parse JSON data", err.Error())
+}
+
+func TestLookupError_NoErrors(t *testing.T) {
+	err := &LookupError{Op: "credly.GetBadgeTemplatesByIds"}
+
+	assert.Equal(t, "[credly.GetBadgeTemplatesByIds] Failed to retrieve items", err.Error())
+	assert.Empty(t, err.Unwrap())
+}
+
+func TestBulkError_NoFailures(t *testing.T) {
+	err := &BulkError{Op: "credly.IssueBadges"}
+
+	assert.Equal(t, "[credly.IssueBadges] Failed to issue badges, 0 succeeded", err.Error())
+	assert.Empty(t, err.Unwrap())
+}
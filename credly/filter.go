@@ -0,0 +1,287 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// BadgeFilter selects the organization's badges to list, see GetBadgesByFilter.
+// Empty fields don't filter, and set fields must all match.
+type BadgeFilter struct {
+	// Email matches any of the email addresses of the recipient.
+	Email string
+
+	// TemplateId matches the ID of the badge template.
+	TemplateId string
+
+	// State matches the state of the badge, one of the BadgeState constants.
+	State string
+
+	// Tags matches badges whose template has any of these reporting tags, see GetBadges.
+	Tags []string
+
+	// IssuedAfter and IssuedBefore bound the issue date of the badge, inclusively.
+	IssuedAfter  time.Time
+	IssuedBefore time.Time
+
+	// UpdatedAfter and UpdatedBefore bound the date the badge was last changed, inclusively.
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+
+	// Sort orders the badges by one of issued_at, created_at, updated_at,
+	// expires_at or recipient_email, descending if prefixed with "-", e.g.
+	// "-issued_at" for the most recent first. Empty uses Credly's default order.
+	Sort string
+}
+
+// badgeSortKeys is the set of keys accepted in BadgeFilter.Sort.
+var badgeSortKeys = map[string]bool{
+	"issued_at":       true,
+	"created_at":      true,
+	"updated_at":      true,
+	"expires_at":      true,
+	"recipient_email": true,
+}
+
+// checkSort returns an error if sort isn't one of keys, optionally prefixed with "-".
+func checkSort(op, sort string, keys map[string]bool) error {
+	if !keys[strings.TrimPrefix(sort, "-")] {
+		return fmt.Errorf("[%s] Unknown sort key: %q", op, sort)
+	}
+	return nil
+}
+
+// Encode returns the filter in Credly's filter query language, e.g.
+// "recipient_email_all::jane@example.com|state::accepted", to be sent in the
+// filter query parameter, or "" if no field is set.
+//
+// The query language has no escaping: values containing "|", or tags containing
+// ",", can't be expressed and are rejected with an error.
+func (f BadgeFilter) Encode() (string, error) {
+	var terms []string
+	add := func(name, value string) error {
+		if strings.Contains(value, "|") {
+			return fmt.Errorf("[credly.BadgeFilter] Invalid %s, must not contain \"|\": %q", name, value)
+		}
+		terms = append(terms, name+"::"+value)
+		return nil
+	}
+
+	if f.Email != "" {
+		if err := add("recipient_email_all", f.Email); err != nil {
+			return "", err
+		}
+	}
+	if f.TemplateId != "" {
+		if err := add("badge_template_id", f.TemplateId); err != nil {
+			return "", err
+		}
+	}
+	if f.State != "" {
+		if !badgeStates[f.State] {
+			return "", fmt.Errorf("[credly.BadgeFilter] Unknown badge state: %q", f.State)
+		}
+		terms = append(terms, "state::"+f.State)
+	}
+	if len(f.Tags) > 0 {
+		for _, tag := range f.Tags {
+			if tag == "" || strings.Contains(tag, ",") {
+				return "", fmt.Errorf("[credly.BadgeFilter] Invalid tag, must be non-empty and not contain \",\": %q", tag)
+			}
+		}
+		if err := add("badge_templates[reporting_tags]", strings.Join(f.Tags, ",")); err != nil {
+			return "", err
+		}
+	}
+	if !f.IssuedAfter.IsZero() {
+		terms = append(terms, "issued_at_min::"+f.IssuedAfter.UTC().Format(time.RFC3339))
+	}
+	if !f.IssuedBefore.IsZero() {
+		terms = append(terms, "issued_at_max::"+f.IssuedBefore.UTC().Format(time.RFC3339))
+	}
+	if !f.UpdatedAfter.IsZero() {
+		terms = append(terms, "updated_at_min::"+f.UpdatedAfter.UTC().Format(time.RFC3339))
+	}
+	if !f.UpdatedBefore.IsZero() {
+		terms = append(terms, "updated_at_max::"+f.UpdatedBefore.UTC().Format(time.RFC3339))
+	}
+
+	return strings.Join(terms, "|"), nil
+}
+
+// GetBadgesByFilter retrieves the organization's badges matching filter.
+// See GetBadgesByFilterContext.
+func (c *Client) GetBadgesByFilter(filter BadgeFilter) ([]BadgeInfo, error) {
+	return c.GetBadgesByFilterContext(context.Background(), filter)
+}
+
+// GetBadgesByFilterContext retrieves the organization's badges matching filter.
+// All pages of results are fetched.
+//
+// With lenient decoding enabled (see WithLenientDecoding), malformed badges are
+// skipped and reported in a *PartialDecodeError returned alongside the valid ones.
+//
+// ctx: The context controlling the requests.
+// filter: The criteria the badges must match.
+// Returns: A slice of BadgeInfo representing the matching badges, or an error if the filter is invalid or the operation fails.
+func (c *Client) GetBadgesByFilterContext(ctx context.Context, filter BadgeFilter) ([]BadgeInfo, error) {
+	qUrl, err := c.filteredBadgesUrl(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return listAll[BadgeInfo](ctx, c, "credly.GetBadgesByFilter", qUrl)
+}
+
+// GetBadgesSince retrieves the organization's badges changed since t.
+// See GetBadgesSinceContext.
+func (c *Client) GetBadgesSince(t time.Time) ([]BadgeInfo, error) {
+	return c.GetBadgesSinceContext(context.Background(), t)
+}
+
+// GetBadgesSinceContext retrieves the organization's badges changed since t,
+// e.g. for an incremental sync. All pages of results are fetched.
+//
+// Badges are filtered on their updated_at timestamp (see BadgeInfo.UpdatedAt),
+// which Credly sets when a badge is issued and on every later change, such as
+// its acceptance or revocation. The result therefore includes both the badges
+// issued since t and the older ones that changed since t. Badges updated
+// exactly at t are included, so t can be the UpdatedAt of the most recent
+// badge of the previous sync.
+//
+// ctx: The context controlling the requests.
+// t: The time of the previous sync.
+// Returns: A slice of BadgeInfo representing the changed badges, or an error if the operation fails.
+func (c *Client) GetBadgesSinceContext(ctx context.Context, t time.Time) ([]BadgeInfo, error) {
+	if t.IsZero() {
+		return nil, fmt.Errorf("[credly.GetBadgesSince] Time must not be zero")
+	}
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{UpdatedAfter: t})
+	if err != nil {
+		return nil, err
+	}
+
+	return listAll[BadgeInfo](ctx, c, "credly.GetBadgesSince", qUrl)
+}
+
+// GetBadgesModifiedBetween retrieves the organization's badges issued or
+// revoked between start and end. See GetBadgesModifiedBetweenContext.
+func (c *Client) GetBadgesModifiedBetween(start, end time.Time) ([]BadgeInfo, error) {
+	return c.GetBadgesModifiedBetweenContext(context.Background(), start, end)
+}
+
+// GetBadgesModifiedBetweenContext retrieves the organization's badges issued
+// or revoked between start and end, inclusively, e.g. for an audit report of
+// the badges issued or revoked during a quarter. All pages of results are
+// fetched.
+//
+// Badges are matched on their issued_at and revoked_at timestamps, so a badge
+// changed again after end, e.g. accepted, is still included. Badges issued
+// before start are only included if they were revoked during the range.
+//
+// ctx: The context controlling the requests.
+// start: The beginning of the range.
+// end: The end of the range, not before start.
+// Returns: A slice of BadgeInfo representing the badges issued during the range, followed by those
+// issued before but revoked during it, or an error if the range is invalid or the operation fails.
+func (c *Client) GetBadgesModifiedBetweenContext(ctx context.Context, start, end time.Time) ([]BadgeInfo, error) {
+	if start.IsZero() || end.IsZero() {
+		return nil, fmt.Errorf("[credly.GetBadgesModifiedBetween] Start and end must not be zero")
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("[credly.GetBadgesModifiedBetween] Start must not be after end")
+	}
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{IssuedAfter: start, IssuedBefore: end})
+	if err != nil {
+		return nil, err
+	}
+
+	badges, err := listAll[BadgeInfo](ctx, c, "credly.GetBadgesModifiedBetween", qUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	// Credly doesn't filter on revoked_at, but a badge revoked during the
+	// range was last updated after start.
+	qUrl, err = c.filteredBadgesUrl(BadgeFilter{State: BadgeStateRevoked, UpdatedAfter: start})
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := listAll[BadgeInfo](ctx, c, "credly.GetBadgesModifiedBetween", qUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(badges))
+	for _, b := range badges {
+		seen[b.Id] = true
+	}
+	for _, b := range revoked {
+		if seen[b.Id] || b.RevokedAt.Before(start) || b.RevokedAt.After(end) {
+			continue
+		}
+		badges = append(badges, b)
+	}
+
+	return badges, nil
+}
+
+// withQueryParam appends a query parameter to rawUrl.
+func withQueryParam(rawUrl, key, value string) string {
+	sep := "&"
+	if !strings.Contains(rawUrl, "?") {
+		sep = "?"
+	}
+	return fmt.Sprintf("%s%s%s=%s", rawUrl, sep, url.QueryEscape(key), url.QueryEscape(value))
+}
+
+// checkNotEmpty returns an error if value, a parameter scoping a request to a
+// recipient or a badge template, is empty. Left out of the filter, it would
+// match the badges of the whole organization instead.
+func checkNotEmpty(op, name, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("[%s] %s must not be empty", op, name)
+	}
+	return nil
+}
+
+// filteredBadgesUrl builds the URL listing the organization's badges matching filter.
+func (c *Client) filteredBadgesUrl(filter BadgeFilter) (string, error) {
+	qUrl := fmt.Sprintf("%s/organizations/%s/badges", c.baseURL(), c.OrganizationId)
+
+	f, err := filter.Encode()
+	if err != nil {
+		return "", err
+	}
+	if f != "" {
+		qUrl = fmt.Sprintf("%s?filter=%s", qUrl, url.QueryEscape(f))
+	}
+	if filter.Sort != "" {
+		if err := checkSort("credly.BadgeFilter", filter.Sort, badgeSortKeys); err != nil {
+			return "", err
+		}
+		qUrl = withQueryParam(qUrl, "sort", filter.Sort)
+	}
+
+	return qUrl, nil
+}
@@ -0,0 +1,253 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBadgeFilter_Encode(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   BadgeFilter
+		expected string
+		err      string
+	}{
+		{name: "empty", filter: BadgeFilter{}, expected: ""},
+		{name: "email", filter: BadgeFilter{Email: "jane+certs@example.com"}, expected: "recipient_email_all::jane+certs@example.com"},
+		{
+			name: "all fields",
+			filter: BadgeFilter{
+				Email:         "jane@example.com",
+				TemplateId:    "template-123",
+				State:         BadgeStateIssued,
+				Tags:          []string{"security", "networking"},
+				IssuedAfter:   time.Date(2024, time.January, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600)),
+				IssuedBefore:  time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC),
+				UpdatedAfter:  time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedBefore: time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC),
+			},
+			expected: "recipient_email_all::jane@example.com|badge_template_id::template-123|state::issued|" +
+				"badge_templates[reporting_tags]::security,networking|issued_at_min::2024-01-01T00:00:00Z|issued_at_max::2024-12-31T00:00:00Z|" +
+				"updated_at_min::2024-06-01T00:00:00Z|updated_at_max::2024-06-30T00:00:00Z",
+		},
+		{name: "unknown state", filter: BadgeFilter{State: "archived"}, err: "Unknown badge state"},
+		{name: "separator in value", filter: BadgeFilter{Email: "a@example.com|state::revoked"}, err: `must not contain "|"`},
+		{name: "comma in tag", filter: BadgeFilter{Tags: []string{"a,b"}}, err: `not contain ","`},
+		{name: "empty tag", filter: BadgeFilter{Tags: []string{""}}, err: "must be non-empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.filter.Encode()
+
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, encoded)
+		})
+	}
+}
+
+func TestGetBadgesByFilter(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	responseBody, _ := json.Marshal(getBadgesResponse{
+		Data: []BadgeInfo{{Id: "badge-123"}},
+	})
+
+	// Special characters are escaped in the query string
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "recipient_email_all::jane+certs@example.com|state::accepted" &&
+			!bytes.Contains([]byte(req.URL.RawQuery), []byte("+certs"))
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	badges, err := client.GetBadgesByFilter(BadgeFilter{Email: "jane+certs@example.com", State: BadgeStateAccepted})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeInfo{{Id: "badge-123"}}, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgesByFilter_Invalid(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	_, err := client.GetBadgesByFilter(BadgeFilter{State: "archived"})
+
+	assert.Error(t, err)
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestGetBadgesByFilter_Sort(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("sort") == "-issued_at" && req.URL.Query().Get("page") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body: io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "badge-2"}],
+			"metadata": {"next_page_url": "https://api.credly.com/v1/organizations/org-123/badges?page=2"}}`)),
+	}, nil).Once()
+	// The sort order is kept on the following pages
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("sort") == "-issued_at" && req.URL.Query().Get("page") == "2"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "badge-1"}]}`)),
+	}, nil).Once()
+
+	badges, err := client.GetBadgesByFilter(BadgeFilter{Sort: "-issued_at"})
+
+	assert.NoError(t, err)
+	assert.Len(t, badges, 2)
+	mockClient.AssertExpectations(t)
+
+	_, err = client.GetBadgesByFilter(BadgeFilter{Sort: "-popularity"})
+	assert.ErrorContains(t, err, "Unknown sort key")
+}
+
+func TestGetBadgesSince(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/organizations/org-123/badges" &&
+			req.URL.Query().Get("filter") == "updated_at_min::2024-06-01T08:00:00Z"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "badge-123", "updated_at": "2024-06-02T10:00:00Z"}]}`)),
+	}, nil).Once()
+
+	badges, err := client.GetBadgesSince(time.Date(2024, time.June, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*3600)))
+
+	assert.NoError(t, err)
+	assert.Len(t, badges, 1)
+	assert.True(t, time.Date(2024, time.June, 2, 10, 0, 0, 0, time.UTC).Equal(badges[0].UpdatedAt))
+	mockClient.AssertExpectations(t)
+
+	_, err = client.GetBadgesSince(time.Time{})
+	assert.Error(t, err)
+}
+
+func TestGetBadgesModifiedBetween(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	start := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, time.June, 30, 23, 59, 59, 0, time.UTC)
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "issued_at_min::2024-04-01T00:00:00Z|issued_at_max::2024-06-30T23:59:59Z"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "badge-123", "state": "revoked", "revoked_at": "2024-05-01T00:00:00Z"}]}`)),
+	}, nil).Once()
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "state::revoked|updated_at_min::2024-04-01T00:00:00Z"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body: io.NopCloser(bytes.NewBufferString(`{"data": [
+			{"id": "badge-123", "state": "revoked", "revoked_at": "2024-05-01T00:00:00Z"},
+			{"id": "badge-456", "state": "revoked", "revoked_at": "2024-06-01T00:00:00Z"},
+			{"id": "badge-789", "state": "revoked", "revoked_at": "2024-07-01T00:00:00Z"}
+		]}`)),
+	}, nil).Once()
+
+	badges, err := client.GetBadgesModifiedBetween(start, end)
+
+	// Badges revoked during the range are added once, those revoked after end aren't
+	assert.NoError(t, err)
+	assert.Len(t, badges, 2)
+	assert.Equal(t, "badge-123", badges[0].Id)
+	assert.Equal(t, "badge-456", badges[1].Id)
+	mockClient.AssertExpectations(t)
+
+	_, err = client.GetBadgesModifiedBetween(end, start)
+	assert.ErrorContains(t, err, "Start must not be after end")
+
+	_, err = client.GetBadgesModifiedBetween(time.Time{}, end)
+	assert.Error(t, err)
+
+	// A single instant is a valid range
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": []}`)),
+	}, nil).Once()
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": []}`)),
+	}, nil).Once()
+
+	_, err = client.GetBadgesModifiedBetween(start, start)
+	assert.NoError(t, err)
+}
+
+func TestScopedMethods_RejectEmptyScope(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	// Without these checks, the filter would match every badge of the organization
+	_, err := client.GetBadges("", nil)
+	assert.ErrorContains(t, err, "[credly.GetBadges] Email must not be empty")
+
+	_, _, err = client.GetBadgesPage(" ", nil, 1)
+	assert.Error(t, err)
+
+	_, err = client.GetBadgesByState("", BadgeStateAccepted)
+	assert.Error(t, err)
+
+	_, err = client.GetActiveBadges("")
+	assert.Error(t, err)
+
+	_, err = client.GetBadge("", "template-123")
+	assert.ErrorContains(t, err, "Email must not be empty")
+
+	_, err = client.GetBadge("jane@example.com", "")
+	assert.ErrorContains(t, err, "Template ID must not be empty")
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	_, err = client.GetBadgesByTemplate("")
+	assert.Error(t, err)
+
+	_, err = client.IterateBadges("", nil).Next(context.Background())
+	assert.Error(t, err)
+
+	_, err = client.GetBadgeTemplateStats("")
+	assert.Error(t, err)
+
+	_, err = client.GetBadgesForEmails([]string{""}, 1)
+	var lookupErr *LookupError
+	assert.ErrorAs(t, err, &lookupErr)
+
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
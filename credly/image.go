@@ -0,0 +1,149 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DownloadTemplateImageTo streams the image of a badge template to w.
+// See DownloadTemplateImageToContext.
+func (c *Client) DownloadTemplateImageTo(t BadgeTemplate, w io.Writer) (string, error) {
+	return c.DownloadTemplateImageToContext(context.Background(), t, w)
+}
+
+// DownloadTemplateImageToContext streams the image of a badge template to w,
+// without buffering the whole image in memory, e.g. to write it to a file or
+// an HTTP response.
+//
+// Images are served from Credly's public CDN, so no authentication headers are sent.
+//
+// ctx: The context controlling the request. Cancelling it aborts the download, even mid-stream.
+// t: The badge template whose image to download.
+// w: The writer the image is copied to.
+// Returns: The content type of the image, or an error if the operation fails.
+func (c *Client) DownloadTemplateImageToContext(ctx context.Context, t BadgeTemplate, w io.Writer) (string, error) {
+	if t.ImageUrl == "" {
+		return "", fmt.Errorf("[credly.DownloadTemplateImageTo] Template %s has no image URL", t.Id)
+	}
+
+	return c.downloadImage(ctx, "credly.DownloadTemplateImageTo", t.ImageUrl, w)
+}
+
+// DownloadBadgeImage downloads the image of an issued badge, e.g. to cache it
+// rather than hotlinking Credly's CDN. See DownloadBadgeImageContext.
+func (c *Client) DownloadBadgeImage(b BadgeInfo) ([]byte, string, error) {
+	return c.DownloadBadgeImageContext(context.Background(), b)
+}
+
+// DownloadBadgeImageContext downloads the image of an issued badge. The image
+// is taken from ImageUrl, falling back to Image.Url.
+//
+// Images are served from Credly's public CDN, so no authentication headers are sent.
+//
+// ctx: The context controlling the request.
+// b: The badge whose image to download.
+// Returns: The image data and its content type, or an error if the operation fails.
+func (c *Client) DownloadBadgeImageContext(ctx context.Context, b BadgeInfo) ([]byte, string, error) {
+	return c.downloadBadgeImage(ctx, "credly.DownloadBadgeImage", b)
+}
+
+// GetBadgeImageDataURI downloads the image of an issued badge as a data URI.
+// See GetBadgeImageDataURIContext.
+func (c *Client) GetBadgeImageDataURI(b BadgeInfo) (string, error) {
+	return c.GetBadgeImageDataURIContext(context.Background(), b)
+}
+
+// GetBadgeImageDataURIContext downloads the image of an issued badge, like
+// DownloadBadgeImageContext, and returns it as a base64 data URI, e.g.
+// "data:image/png;base64,iVBORw0KGgo...", to embed it in an email.
+//
+// ctx: The context controlling the request.
+// b: The badge whose image to download.
+// Returns: The data URI of the image, or an error if the operation fails or the content isn't an image.
+func (c *Client) GetBadgeImageDataURIContext(ctx context.Context, b BadgeInfo) (string, error) {
+	data, contentType, err := c.downloadBadgeImage(ctx, "credly.GetBadgeImageDataURI", b)
+	if err != nil {
+		return "", err
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		return "", fmt.Errorf("[credly.GetBadgeImageDataURI] Badge %s image has unexpected content type: %q", b.Id, contentType)
+	}
+
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// downloadBadgeImage downloads the image of an issued badge, taken from
+// ImageUrl, falling back to Image.Url, and returns it with its content type.
+func (c *Client) downloadBadgeImage(ctx context.Context, op string, b BadgeInfo) ([]byte, string, error) {
+	imageUrl := b.ImageUrl
+	if imageUrl == "" {
+		imageUrl = b.Image.Url
+	}
+	if imageUrl == "" {
+		return nil, "", fmt.Errorf("[%s] Badge %s has no image URL", op, b.Id)
+	}
+
+	var buf bytes.Buffer
+	contentType, err := c.downloadImage(ctx, op, imageUrl, &buf)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), contentType, nil
+}
+
+// downloadImage streams the image at imageUrl to w and returns its content type.
+// The content type is sniffed from the image data if the server doesn't send one.
+func (c *Client) downloadImage(ctx context.Context, op, imageUrl string, w io.Writer) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", imageUrl, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// Bypass Do, the CDN is public and must not receive the API credentials
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError(op, resp)
+	}
+
+	body := bufio.NewReader(resp.Body)
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		head, _ := body.Peek(512)
+		contentType = http.DetectContentType(head)
+	}
+
+	if _, err := io.Copy(w, body); err != nil {
+		return "", fmt.Errorf("[%s] Failed to download image: %w", op, err)
+	}
+
+	return contentType, nil
+}
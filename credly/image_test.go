@@ -0,0 +1,159 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// pngHeader is the signature of a PNG file, enough for content type sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestDownloadTemplateImageTo(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123")
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://images.credly.com/template.png" && req.Header.Get("Authorization") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"image/png"}},
+		Body:       io.NopCloser(bytes.NewReader(pngHeader)),
+	}, nil)
+
+	var buf bytes.Buffer
+	contentType, err := client.DownloadTemplateImageTo(BadgeTemplate{ImageUrl: "https://images.credly.com/template.png"}, &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, pngHeader, buf.Bytes())
+	mockClient.AssertExpectations(t)
+}
+
+func TestDownloadTemplateImageTo_SniffContentType(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(pngHeader)),
+	}, nil)
+
+	var buf bytes.Buffer
+	contentType, err := client.DownloadTemplateImageTo(BadgeTemplate{ImageUrl: "https://images.credly.com/template.png"}, &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, pngHeader, buf.Bytes())
+}
+
+func TestDownloadTemplateImageTo_NoImage(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	_, err := client.DownloadTemplateImageTo(BadgeTemplate{Id: "template-123"}, io.Discard)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "template-123 has no image URL")
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestDownloadTemplateImageTo_Canceled(t *testing.T) {
+	client := &Client{HTTPClient: &http.Client{}, OrganizationId: "org-123"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.DownloadTemplateImageToContext(ctx, BadgeTemplate{ImageUrl: "https://images.credly.com/template.png"}, io.Discard)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDownloadBadgeImage(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123")
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://images.credly.com/badge.png" && req.Header.Get("Authorization") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(pngHeader)),
+	}, nil)
+
+	// Falls back to Image.Url when ImageUrl is empty
+	badge := BadgeInfo{Id: "badge-123"}
+	badge.Image.Url = "https://images.credly.com/badge.png"
+
+	data, contentType, err := client.DownloadBadgeImage(badge)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, pngHeader, data)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDownloadBadgeImage_NoImage(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	data, _, err := client.DownloadBadgeImage(BadgeInfo{Id: "badge-123"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "badge-123 has no image URL")
+	assert.Nil(t, data)
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestGetBadgeImageDataURI(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"image/png; charset=binary"}},
+		Body:       io.NopCloser(bytes.NewReader(pngHeader)),
+	}, nil).Once()
+
+	uri, err := client.GetBadgeImageDataURI(BadgeInfo{Id: "badge-123", ImageUrl: "https://images.credly.com/badge.png"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "data:image/png;base64,"+base64.StdEncoding.EncodeToString(pngHeader), uri)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgeImageDataURI_NotAnImage(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/html"}},
+		Body:       io.NopCloser(bytes.NewBufferString("<html></html>")),
+	}, nil).Once()
+
+	_, err := client.GetBadgeImageDataURI(BadgeInfo{Id: "badge-123", ImageUrl: "https://images.credly.com/badge.png"})
+
+	assert.ErrorContains(t, err, "[credly.GetBadgeImageDataURI] Badge badge-123 image has unexpected content type")
+}
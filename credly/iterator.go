@@ -0,0 +1,86 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// BadgeIterator iterates over a list of badges, fetching pages lazily as it
+// advances, so that large lists can be processed without holding every badge
+// in memory. See IterateBadges.
+//
+// A BadgeIterator is not safe for concurrent use.
+type BadgeIterator struct {
+	pager *pager[BadgeInfo]
+
+	// page holds the badges of the current page not yet returned by Next.
+	page []BadgeInfo
+
+	// err is the error returned by Next once the page is exhausted.
+	err error
+}
+
+// IterateBadges returns an iterator over all badges for a given email,
+// optionally filtered by collections. Unlike GetBadges, a page is only fetched
+// once the badges of the previous one have been consumed.
+//
+// With lenient decoding enabled (see WithLenientDecoding), malformed badges are skipped.
+//
+// email: The recipient's email address.
+// collections: A list of collection tags to filter badges, see GetBadges.
+// Returns: A BadgeIterator over the badges.
+func (c *Client) IterateBadges(email string, collections []string) *BadgeIterator {
+	if err := checkNotEmpty("credly.IterateBadges", "Email", email); err != nil {
+		return &BadgeIterator{err: err}
+	}
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{Email: email, Tags: collections})
+	if err != nil {
+		return &BadgeIterator{err: err}
+	}
+
+	p, err := newPager[BadgeInfo](c, "credly.IterateBadges", qUrl)
+	return &BadgeIterator{pager: p, err: err}
+}
+
+// Next returns the next badge, fetching the next page with ctx if needed.
+// It returns io.EOF once every badge was returned. After any other error,
+// Next keeps returning that error.
+func (it *BadgeIterator) Next(ctx context.Context) (BadgeInfo, error) {
+	for len(it.page) == 0 {
+		if it.err != nil {
+			return BadgeInfo{}, it.err
+		}
+		if it.pager.done() {
+			it.err = io.EOF
+			continue
+		}
+
+		items, err := it.pager.next(ctx)
+		var partial *PartialDecodeError
+		if err != nil && !errors.As(err, &partial) {
+			it.err = err
+			continue
+		}
+		it.page = items
+	}
+
+	b := it.page[0]
+	it.page = it.page[1:]
+	return b, nil
+}
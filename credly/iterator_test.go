@@ -0,0 +1,103 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIterateBadges(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-123"}, {Id: "badge-456"}},
+		"metadata": PageMetadata{CurrentPage: 1, TotalPages: 3, NextPageUrl: "https://api.credly.com/v1/organizations/org-123/badges?page=2"},
+	})
+	page2, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{},
+		"metadata": PageMetadata{CurrentPage: 2, TotalPages: 3, NextPageUrl: "https://api.credly.com/v1/organizations/org-123/badges?page=3"},
+	})
+	page3, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-789"}},
+		"metadata": PageMetadata{CurrentPage: 3, TotalPages: 3},
+	})
+
+	onPage := func(page string, body []byte) {
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.Query().Get("filter") == "recipient_email_all::test@example.com" && req.URL.Query().Get("page") == page
+		})).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}, nil).Once()
+	}
+	onPage("", page1)
+	onPage("2", page2)
+	onPage("3", page3)
+
+	it := client.IterateBadges("test@example.com", nil)
+	ctx := context.Background()
+
+	// The first page is only fetched when advancing
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+
+	var ids []string
+	for {
+		b, err := it.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		ids = append(ids, b.Id)
+
+		if b.Id == "badge-123" {
+			// The second page isn't fetched while the first has badges left
+			mockClient.AssertNumberOfCalls(t, "Do", 1)
+		}
+	}
+
+	assert.Equal(t, []string{"badge-123", "badge-456", "badge-789"}, ids)
+	_, err := it.Next(ctx)
+	assert.Equal(t, io.EOF, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestIterateBadges_Error(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	it := client.IterateBadges("test@example.com", nil)
+
+	_, err := it.Next(context.Background())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// The error is sticky and no further request is sent
+	_, err = it.Next(context.Background())
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
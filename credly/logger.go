@@ -0,0 +1,190 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"time"
+)
+
+// Logger receives structured logs of the requests sent to Credly, see WithLogger.
+type Logger interface {
+	// Log is called with the fields describing an event. The "msg" field names
+	// the event, either "credly request" or "credly response". Requests carry
+	// the "method" and "url" fields, responses additionally carry "duration"
+	// and either "status_code" or "error".
+	Log(ctx context.Context, fields map[string]interface{})
+}
+
+// LoggerFunc is an adapter allowing the use of an ordinary function as a Logger.
+type LoggerFunc func(ctx context.Context, fields map[string]interface{})
+
+// Log calls f(ctx, fields).
+func (f LoggerFunc) Log(ctx context.Context, fields map[string]interface{}) {
+	f(ctx, fields)
+}
+
+// WithLogger logs every request sent to Credly, including retries, before it
+// is sent and once it completes. Nothing is logged by default.
+//
+// Request URLs may contain recipient email addresses in their filter.
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// logRequest logs req before it is sent.
+func (c *Client) logRequest(req *http.Request) {
+	if c.logger == nil {
+		return
+	}
+
+	c.logger.Log(req.Context(), map[string]interface{}{
+		"msg":    "credly request",
+		"method": req.Method,
+		"url":    req.URL.String(),
+	})
+}
+
+// logResponse logs the outcome of req, sent at start.
+func (c *Client) logResponse(req *http.Request, resp *http.Response, err error, start time.Time) {
+	if c.logger == nil {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"msg":      "credly response",
+		"method":   req.Method,
+		"url":      req.URL.String(),
+		"duration": time.Since(start),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	} else {
+		fields["status_code"] = resp.StatusCode
+	}
+
+	c.logger.Log(req.Context(), fields)
+}
+
+// LoggingTransport is an http.RoundTripper writing full dumps of the requests
+// and responses going through it, headers and bodies included, e.g. to debug an
+// integration. It can be installed with WithTransport, or wrap a transport
+// that is already instrumented:
+//
+//	credly.WithTransport(&credly.LoggingTransport{Writer: os.Stderr})
+//
+// The Authorization header is redacted, but the dumps contain the recipients'
+// personal data, so they must not be enabled in production. Responses are
+// buffered in memory to be dumped.
+type LoggingTransport struct {
+	// Transport sends the requests. Nil means http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Writer receives the dumps.
+	Writer io.Writer
+
+	// mu keeps the dumps of concurrent requests from interleaving.
+	mu sync.Mutex
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt := t.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	dump, out, err := dumpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	t.write(dump)
+
+	resp, err := rt.RoundTrip(out)
+	if err != nil {
+		t.write([]byte(fmt.Sprintf("%s %s failed: %v\n", req.Method, req.URL, err)))
+		return resp, err
+	}
+	if resp.Request == out {
+		resp.Request = req
+	}
+
+	dump, err = httputil.DumpResponse(resp, true)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	t.write(dump)
+
+	return resp, nil
+}
+
+// write writes a dump to Writer, separated from the next one by a blank line.
+func (t *LoggingTransport) write(dump []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, _ = t.Writer.Write(append(bytes.TrimRight(dump, "\r\n"), "\n\n"...))
+}
+
+// dumpRequest dumps req with its Authorization header redacted. The body is
+// read from req.GetBody if set. Otherwise it is buffered, and the returned
+// request to send in place of req is a shallow copy of req carrying the
+// buffered body, as a RoundTripper must not modify req.
+func dumpRequest(req *http.Request) ([]byte, *http.Request, error) {
+	out := req
+	r := req.Clone(req.Context())
+	if r.Header.Get("Authorization") != "" {
+		r.Header.Set("Authorization", "REDACTED")
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, nil, err
+			}
+			r.Body = body
+		} else {
+			body, err := io.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			out = new(http.Request)
+			*out = *req
+			out.Body = io.NopCloser(bytes.NewReader(body))
+			out.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+	}
+
+	dump, err := httputil.DumpRequestOut(r, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dump, out, nil
+}
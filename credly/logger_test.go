@@ -0,0 +1,153 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWithLogger(t *testing.T) {
+	var logs []map[string]interface{}
+	logger := LoggerFunc(func(ctx context.Context, fields map[string]interface{}) {
+		logs = append(logs, fields)
+	})
+
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithLogger(logger))
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{StatusCode: http.StatusOK}, nil)
+
+	req, err := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
+	assert.NoError(t, err)
+
+	_, err = client.Do(req)
+
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, map[string]interface{}{
+		"msg":    "credly request",
+		"method": "GET",
+		"url":    "https://api.credly.com/v1/some-endpoint",
+	}, logs[0])
+	assert.Equal(t, "credly response", logs[1]["msg"])
+	assert.Equal(t, http.StatusOK, logs[1]["status_code"])
+	assert.IsType(t, time.Duration(0), logs[1]["duration"])
+}
+
+func TestWithLogger_Error(t *testing.T) {
+	var logs []map[string]interface{}
+	logger := LoggerFunc(func(ctx context.Context, fields map[string]interface{}) {
+		logs = append(logs, fields)
+	})
+
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithLogger(logger))
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.Anything).Return((*http.Response)(nil), errors.New("connection refused"))
+
+	req, err := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
+	assert.NoError(t, err)
+
+	_, err = client.Do(req)
+
+	assert.Error(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "connection refused", logs[1]["error"])
+	assert.NotContains(t, logs[1], "status_code")
+}
+
+func TestLoggingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data": {"id": "badge-123"}}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient("test-token", "org-123",
+		WithBaseURL(server.URL+"/v1"),
+		WithTransport(&LoggingTransport{Writer: &buf}))
+
+	badge, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "badge-123", badge.Id)
+
+	dump := buf.String()
+	assert.Contains(t, dump, "POST /v1/organizations/org-123/badges HTTP/1.1")
+	assert.Contains(t, dump, `"recipient_email":"test@example.com"`)
+	assert.Contains(t, dump, "HTTP/1.1 201 Created")
+	assert.Contains(t, dump, `{"data": {"id": "badge-123"}}`)
+	// The credentials are redacted
+	assert.Contains(t, dump, "Authorization: REDACTED")
+	assert.NotContains(t, dump, encodeToken("test-token"))
+}
+
+func TestLoggingTransport_Error(t *testing.T) {
+	var buf bytes.Buffer
+	transport := &LoggingTransport{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		}),
+		Writer: &buf,
+	}
+
+	req, _ := http.NewRequest("POST", "https://api.credly.com/v1/some-endpoint", strings.NewReader("payload"))
+	_, err := transport.RoundTrip(req)
+
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "payload")
+	assert.Contains(t, buf.String(), "POST https://api.credly.com/v1/some-endpoint failed: connection refused")
+}
+
+func TestLoggingTransport_UnbufferedBody(t *testing.T) {
+	var buf bytes.Buffer
+	var sent string
+	transport := &LoggingTransport{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			sent = string(body)
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Request: req}, nil
+		}),
+		Writer: &buf,
+	}
+
+	// A body without GetBody is dumped and sent without changing the request
+	body := io.NopCloser(strings.NewReader("payload"))
+	req, _ := http.NewRequest("POST", "https://api.credly.com/v1/some-endpoint", body)
+	req.GetBody = nil
+
+	resp, err := transport.RoundTrip(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "payload", sent)
+	assert.Contains(t, buf.String(), "payload")
+	assert.Equal(t, body, req.Body)
+	assert.Nil(t, req.GetBody)
+	assert.Same(t, req, resp.Request)
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MetricsRecorder receives a measurement of every request sent to Credly, see WithMetrics.
+type MetricsRecorder interface {
+	// ObserveRequest is called once a request completes. endpoint is the name
+	// of the client method that sent it, e.g. "IssueBadge" or "GetBadges", or
+	// "Do" for requests sent directly with Client.Do. statusCode is 0 if no
+	// response was received.
+	ObserveRequest(endpoint string, statusCode int, duration time.Duration)
+}
+
+// WithMetrics reports every request sent to Credly, including retries, to m.
+// Nothing is recorded by default.
+func WithMetrics(m MetricsRecorder) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// observeRequest records the outcome of req, sent at start.
+func (c *Client) observeRequest(req *http.Request, resp *http.Response, start time.Time) {
+	if c.metrics == nil {
+		return
+	}
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+
+	c.metrics.ObserveRequest(endpoint(req.Context()), statusCode, time.Since(start))
+}
+
+// operationKey is the context key holding the name of the client method
+// sending a request.
+type operationKey struct{}
+
+// withOperation returns a copy of ctx recording that requests are sent on
+// behalf of op, e.g. "credly.IssueBadge".
+func withOperation(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// operation returns the name of the client method sending requests with ctx,
+// e.g. "credly.IssueBadge", or "" if unknown.
+func operation(ctx context.Context) string {
+	op, _ := ctx.Value(operationKey{}).(string)
+	return op
+}
+
+// endpoint returns the name of the client method sending requests with ctx,
+// without the package prefix, or "Do" if unknown.
+func endpoint(ctx context.Context) string {
+	op := operation(ctx)
+	if op == "" {
+		return "Do"
+	}
+	return strings.TrimPrefix(op, "credly.")
+}
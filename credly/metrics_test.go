@@ -0,0 +1,108 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMetricsRecorder is a mock implementation of the MetricsRecorder.
+type MockMetricsRecorder struct {
+	mock.Mock
+}
+
+func (m *MockMetricsRecorder) ObserveRequest(endpoint string, statusCode int, duration time.Duration) {
+	m.Called(endpoint, statusCode, duration)
+}
+
+func TestWithMetrics(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	recorder := new(MockMetricsRecorder)
+	client := NewClient("test-token", "org-123", WithMetrics(recorder))
+	client.HTTPClient = mockClient
+	client.MaxRetries = 3
+	client.RetryBaseDelay = time.Millisecond
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil).Once()
+
+	// Every attempt is recorded
+	recorder.On("ObserveRequest", "IssueBadge", http.StatusTooManyRequests, mock.AnythingOfType("time.Duration")).Once()
+	recorder.On("ObserveRequest", "IssueBadge", http.StatusCreated, mock.AnythingOfType("time.Duration")).Once()
+
+	_, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+	assert.NoError(t, err)
+	recorder.AssertExpectations(t)
+}
+
+func TestWithMetrics_Paginated(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	recorder := new(MockMetricsRecorder)
+	client := NewClient("test-token", "org-123", WithMetrics(recorder))
+	client.HTTPClient = mockClient
+
+	responseBody, _ := json.Marshal(getBadgesResponse{
+		Data: []BadgeInfo{{Id: "badge-123"}},
+	})
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+	recorder.On("ObserveRequest", "GetBadges", http.StatusOK, mock.AnythingOfType("time.Duration")).Once()
+
+	_, err := client.GetBadges("test@example.com", nil)
+
+	assert.NoError(t, err)
+	recorder.AssertExpectations(t)
+}
+
+func TestWithMetrics_TransportError(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	recorder := new(MockMetricsRecorder)
+	client := NewClient("test-token", "org-123", WithMetrics(recorder))
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.Anything).Return((*http.Response)(nil), errors.New("connection refused"))
+	recorder.On("ObserveRequest", "Do", 0, mock.AnythingOfType("time.Duration")).Once()
+
+	req, err := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
+	assert.NoError(t, err)
+
+	_, err = client.Do(req)
+
+	assert.Error(t, err)
+	recorder.AssertExpectations(t)
+}
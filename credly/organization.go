@@ -0,0 +1,50 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Organization represents the details of a Credly organization.
+type Organization struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	PhotoUrl  string `json:"photo_url"`
+	VanityUrl string `json:"vanity_url"`
+}
+
+// GetOrganization retrieves the details of the Client's organization, e.g. to
+// check at startup that OrganizationId is valid.
+//
+// Returns: The Organization, ErrNotFound if Credly responds with 404, or another error if the operation fails.
+func (c *Client) GetOrganization() (o Organization, err error) {
+	url := fmt.Sprintf("%s/organizations/%s", c.baseURL(), c.OrganizationId)
+
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "credly.GetOrganization"), "GET", url, nil)
+	if err != nil {
+		return o, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return o, err
+	}
+	defer resp.Body.Close()
+
+	return decodeResponse[Organization]("credly.GetOrganization", resp, http.StatusOK)
+}
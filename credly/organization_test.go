@@ -0,0 +1,75 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// getOrganizationResponse represents the body of a Credly response returning an organization.
+type getOrganizationResponse struct {
+	Data Organization `json:"data"`
+}
+
+func TestGetOrganization(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	expectedOrg := Organization{
+		Id:        "org-123",
+		Name:      "Isovalent",
+		PhotoUrl:  "https://images.credly.com/org.png",
+		VanityUrl: "https://www.credly.com/organizations/isovalent",
+	}
+
+	responseBody, _ := json.Marshal(getOrganizationResponse{
+		Data: expectedOrg,
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/organizations/org-123"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil)
+
+	org, err := client.GetOrganization()
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedOrg, org)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetOrganization_NotFound(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-404"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil)
+
+	_, err := client.GetOrganization()
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the client to rps requests per second, spacing requests
+// evenly so bulk operations stay under Credly's rate limits. Retries count
+// towards the limit. A value of zero or less disables rate limiting, which is
+// the default.
+//
+// Clients returned by Env share the rate limit of their parent.
+func WithRateLimit(rps int) Option {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = &rateLimiter{interval: time.Second / time.Duration(rps)}
+	}
+}
+
+// rateLimiter spaces events by a fixed interval.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+
+	// next is the earliest time the next event is allowed.
+	next time.Time
+}
+
+// wait blocks until the next event is allowed, or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	at := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if d := at.Sub(now); d > 0 {
+		return sleep(ctx, d)
+	}
+	return nil
+}
+
+// RateLimitInfo holds the rate limit state reported by Credly in the headers
+// of a response, see LastRateLimit.
+type RateLimitInfo struct {
+	// Limit is the number of requests allowed in the current window.
+	Limit int
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+
+	// Reset is the time the current window ends, or the zero time if unknown.
+	Reset time.Time
+}
+
+// LastRateLimit returns the rate limit state reported by Credly in the most
+// recent response carrying rate limit headers, e.g. to slow down a batch job
+// before it gets throttled. It returns false if no such response was received
+// yet, or if the Client wasn't created with NewClient.
+//
+// Clients returned by Env track their rate limit separately from their parent.
+func (c *Client) LastRateLimit() (RateLimitInfo, bool) {
+	if c.rateLimit == nil {
+		return RateLimitInfo{}, false
+	}
+
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	return c.rateLimit.info, c.rateLimit.ok
+}
+
+// rateLimitTracker holds the last rate limit state reported by Credly.
+type rateLimitTracker struct {
+	mu   sync.Mutex
+	info RateLimitInfo
+	ok   bool
+}
+
+// observe records the rate limit state in the headers of resp, if any.
+func (t *rateLimitTracker) observe(resp *http.Response, now time.Time) {
+	if t == nil {
+		return
+	}
+
+	info, ok := parseRateLimit(resp.Header, now)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	t.info, t.ok = info, true
+	t.mu.Unlock()
+}
+
+// resetEpochThreshold tells apart X-RateLimit-Reset values that are Unix
+// timestamps from values that are a number of seconds from now.
+const resetEpochThreshold = 1_000_000_000
+
+// parseRateLimit parses the X-RateLimit-* headers. It returns false if
+// X-RateLimit-Remaining is missing or invalid. X-RateLimit-Reset may be a Unix
+// timestamp or a number of seconds from now.
+func parseRateLimit(h http.Header, now time.Time) (RateLimitInfo, bool) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimitInfo{}, false
+	}
+
+	info := RateLimitInfo{Remaining: remaining}
+	info.Limit, _ = strconv.Atoi(h.Get("X-RateLimit-Limit"))
+
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		if reset >= resetEpochThreshold {
+			info.Reset = time.Unix(reset, 0)
+		} else {
+			info.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+
+	return info, true
+}
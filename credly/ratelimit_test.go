@@ -0,0 +1,133 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithRateLimit(50))
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{StatusCode: http.StatusOK}, nil)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
+		assert.NoError(t, err)
+
+		_, err = client.Do(req)
+		assert.NoError(t, err)
+	}
+
+	// The first request is sent immediately, the next two 20ms apart
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	mockClient.AssertNumberOfCalls(t, "Do", 3)
+}
+
+func TestWithRateLimit_ContextCanceled(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithRateLimit(1))
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{StatusCode: http.StatusOK}, nil).Once()
+
+	req, err := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
+	assert.NoError(t, err)
+	_, err = client.Do(req)
+	assert.NoError(t, err)
+
+	// The next request has to wait a second, which the context doesn't allow
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req, err = http.NewRequestWithContext(ctx, "GET", "https://api.credly.com/v1/some-endpoint", nil)
+	assert.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Do(req)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	mockClient.AssertExpectations(t)
+}
+
+func TestWithRateLimit_Disabled(t *testing.T) {
+	client := NewClient("test-token", "org-123", WithRateLimit(10), WithRateLimit(0))
+
+	assert.Nil(t, client.limiter)
+}
+
+func TestLastRateLimit(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithEnvironment("sandbox", Environment{Token: "sandbox-token"}))
+	client.HTTPClient = mockClient
+
+	_, ok := client.LastRateLimit()
+	assert.False(t, ok)
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"X-Ratelimit-Limit":     []string{"100"},
+			"X-Ratelimit-Remaining": []string{"42"},
+			"X-Ratelimit-Reset":     []string{"1700000000"},
+		},
+		Body: io.NopCloser(strings.NewReader("")),
+	}, nil)
+
+	req, err := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
+	assert.NoError(t, err)
+	_, err = client.Do(req)
+	assert.NoError(t, err)
+
+	info, ok := client.LastRateLimit()
+
+	assert.True(t, ok)
+	assert.Equal(t, RateLimitInfo{Limit: 100, Remaining: 42, Reset: time.Unix(1700000000, 0)}, info)
+
+	// Environments track their own rate limit
+	sandbox, err := client.Env("sandbox")
+	assert.NoError(t, err)
+	_, ok = sandbox.LastRateLimit()
+	assert.False(t, ok)
+}
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	info, ok := parseRateLimit(http.Header{
+		"X-Ratelimit-Remaining": []string{"0"},
+		"X-Ratelimit-Reset":     []string{"30"},
+	}, now)
+	assert.True(t, ok)
+	assert.Equal(t, RateLimitInfo{Remaining: 0, Reset: now.Add(30 * time.Second)}, info)
+
+	_, ok = parseRateLimit(http.Header{}, now)
+	assert.False(t, ok)
+
+	_, ok = parseRateLimit(http.Header{"X-Ratelimit-Remaining": []string{"many"}}, now)
+	assert.False(t, ok)
+}
@@ -0,0 +1,373 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// rawListResponse represents a list response whose items are decoded separately.
+type rawListResponse struct {
+	Data     json.RawMessage `json:"data"`
+	Metadata PageMetadata    `json:"metadata"`
+}
+
+// PageMetadata holds the pagination metadata Credly returns with list responses.
+type PageMetadata struct {
+	// Count is the number of items in the page.
+	Count int `json:"count"`
+	// CurrentPage is the 1-based index of the page.
+	CurrentPage int `json:"current_page"`
+	// TotalCount is the number of items across all pages.
+	TotalCount      int    `json:"total_count"`
+	TotalPages      int    `json:"total_pages"`
+	PerPage         int    `json:"per_page"`
+	PreviousPageUrl string `json:"previous_page_url"`
+	// NextPageUrl is the URL of the next page, or empty on the last page.
+	NextPageUrl string `json:"next_page_url"`
+}
+
+// dataList holds the "data" member of a Credly response listing resources.
+// It tolerates endpoints returning a single object instead of an array,
+// normalizing both shapes to a slice.
+type dataList[T any] []T
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *dataList[T]) UnmarshalJSON(b []byte) error {
+	items, err := unmarshalData[T](b)
+	if err != nil {
+		return err
+	}
+	*d = items
+	return nil
+}
+
+// unmarshalData decodes a "data" member that may be either a JSON array or a
+// single JSON object into a slice. A null member yields an empty slice.
+func unmarshalData[T any](raw json.RawMessage) ([]T, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return []T{}, nil
+	}
+
+	if trimmed[0] == '{' {
+		var item T
+		if err := json.Unmarshal(trimmed, &item); err != nil {
+			return nil, err
+		}
+		return []T{item}, nil
+	}
+
+	var items []T
+	if err := json.Unmarshal(trimmed, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// resourceResponse represents a response holding a single resource in its data
+// member, or an errors array instead when the request failed.
+type resourceResponse struct {
+	Data   json.RawMessage   `json:"data"`
+	Errors []json.RawMessage `json:"errors"`
+}
+
+// decodeResponse checks that resp has the expected status and decodes the
+// resource in its data member. Any other status, or a body holding errors
+// instead of data, is returned as an *APIError carrying the error details.
+func decodeResponse[T any](op string, resp *http.Response, status int) (v T, err error) {
+	if resp.StatusCode != status {
+		return v, newAPIError(op, resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return v, fmt.Errorf("[%s] Failed to read response: %v", op, err)
+	}
+
+	var r resourceResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return v, fmt.Errorf("[%s] Failed to parse JSON data: %v (body: %s)", op, err, bodySnippet(body))
+	}
+
+	data := bytes.TrimSpace(r.Data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		if len(r.Errors) > 0 {
+			return v, newAPIErrorFromBody(op, resp, body)
+		}
+		return v, nil
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("[%s] Failed to parse JSON data: %v", op, err)
+	}
+
+	return v, nil
+}
+
+// decodeListResponse decodes a list response body into a slice of items.
+//
+// In lenient mode, items are decoded one by one: malformed items are skipped
+// and reported in a *PartialDecodeError returned alongside the valid items.
+// Otherwise any malformed item fails the whole decode.
+func decodeListResponse[T any](op string, body io.Reader, lenient bool) ([]T, error) {
+	items, _, err := decodeListPage[T](op, body, lenient)
+	return items, err
+}
+
+// decodeListPage decodes a list response body like decodeListResponse, also
+// returning the pagination metadata of the page.
+func decodeListPage[T any](op string, body io.Reader, lenient bool) ([]T, PageMetadata, error) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, PageMetadata{}, fmt.Errorf("[%s] Failed to read response: %v", op, err)
+	}
+
+	var listResp rawListResponse
+	if err := json.Unmarshal(raw, &listResp); err != nil {
+		return nil, listResp.Metadata, fmt.Errorf("[%s] Failed to parse JSON data: %v (body: %s)", op, err, bodySnippet(raw))
+	}
+	meta := listResp.Metadata
+
+	if !lenient {
+		items, err := unmarshalData[T](listResp.Data)
+		if err != nil {
+			return nil, meta, fmt.Errorf("[%s] Failed to parse JSON data: %v", op, err)
+		}
+		return items, meta, nil
+	}
+
+	raws, err := unmarshalData[json.RawMessage](listResp.Data)
+	if err != nil {
+		return nil, meta, fmt.Errorf("[%s] Failed to parse JSON data: %v", op, err)
+	}
+
+	items := make([]T, 0, len(raws))
+	var itemErrs []ItemDecodeError
+	for i, raw := range raws {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			itemErrs = append(itemErrs, ItemDecodeError{Index: i, Raw: raw, Err: err})
+			continue
+		}
+		items = append(items, item)
+	}
+
+	if len(itemErrs) > 0 {
+		return items, meta, &PartialDecodeError{Op: op, Items: itemErrs}
+	}
+	return items, meta, nil
+}
+
+// fetchPage fetches and decodes a single page of a list endpoint.
+func fetchPage[T any](ctx context.Context, c *Client, op, pageUrl string) ([]T, PageMetadata, error) {
+	req, err := http.NewRequestWithContext(withOperation(ctx, op), "GET", c.withPageSize(pageUrl), nil)
+	if err != nil {
+		return nil, PageMetadata{}, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, PageMetadata{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, PageMetadata{}, newAPIError(op, resp)
+	}
+
+	return decodeListPage[T](op, resp.Body, c.lenientDecoding)
+}
+
+// maxPageSize is the largest page size accepted by Credly.
+const maxPageSize = 100
+
+// pageSizeParam is the query parameter setting the number of items per page.
+const pageSizeParam = "page[size]"
+
+// WithPageSize sets the number of items requested per page by list methods,
+// e.g. the maximum to export all badges in fewer requests, or a small size for
+// previews. It is clamped to 100, the largest size accepted by Credly. Zero or
+// less uses Credly's default of 50.
+func WithPageSize(n int) Option {
+	return func(c *Client) {
+		c.pageSize = min(max(n, 0), maxPageSize)
+	}
+}
+
+// withPageSize adds the page size set with WithPageSize to a list URL, unless
+// it already has one.
+func (c *Client) withPageSize(rawUrl string) string {
+	if c.pageSize <= 0 {
+		return rawUrl
+	}
+	if u, err := url.Parse(rawUrl); err != nil || u.Query().Has(pageSizeParam) {
+		return rawUrl
+	}
+	return withQueryParam(rawUrl, pageSizeParam, strconv.Itoa(c.pageSize))
+}
+
+// stickyParams are the query parameters of the first request of listAll that
+// are carried over to the following pages.
+var stickyParams = []string{"filter", "query", "sort", "fields[badge_templates]"}
+
+// listAll fetches every page of a list endpoint, starting at pageUrl and following
+// the next_page_url Credly returns in the response metadata until it is empty.
+//
+// The next_page_url is requested verbatim, since Credly may encode paging state
+// (e.g. cursors) in it, with the authentication headers applied again by Do.
+// To avoid leaking credentials, it must point to the same host as pageUrl. If
+// it lacks the filter, query or sort of the first request, they are added back
+// so that every page is filtered and ordered the same way.
+//
+// In lenient decoding mode, malformed items from all pages are reported in a
+// single *PartialDecodeError, indexed by their position across pages.
+func listAll[T any](ctx context.Context, c *Client, op, pageUrl string) ([]T, error) {
+	p, err := newPager[T](c, op, pageUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	all := []T{}
+	var partial *PartialDecodeError
+	offset := 0
+
+	for !p.done() {
+		items, err := p.next(ctx)
+
+		var pageErr *PartialDecodeError
+		if errors.As(err, &pageErr) {
+			if partial == nil {
+				partial = &PartialDecodeError{Op: op}
+			}
+			for _, item := range pageErr.Items {
+				item.Index += offset
+				partial.Items = append(partial.Items, item)
+			}
+			offset += len(pageErr.Items)
+		} else if err != nil {
+			return nil, err
+		}
+
+		all = append(all, items...)
+		offset += len(items)
+	}
+
+	if partial != nil {
+		return all, partial
+	}
+	return all, nil
+}
+
+// pager fetches the pages of a list endpoint one at a time, following the
+// next_page_url Credly returns in the response metadata, see listAll.
+type pager[T any] struct {
+	c      *Client
+	op     string
+	first  *url.URL
+	sticky url.Values
+
+	// pageUrl is the URL of the next page, or "" once the last page was fetched.
+	pageUrl string
+
+	// fetched holds the URLs of the pages fetched so far, to detect loops.
+	fetched map[string]bool
+}
+
+// newPager returns a pager starting at pageUrl.
+func newPager[T any](c *Client, op, pageUrl string) (*pager[T], error) {
+	first, err := url.Parse(pageUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	sticky := url.Values{}
+	for _, k := range stickyParams {
+		if v := first.Query().Get(k); v != "" {
+			sticky.Set(k, v)
+		}
+	}
+
+	return &pager[T]{c: c, op: op, first: first, sticky: sticky, pageUrl: pageUrl, fetched: map[string]bool{}}, nil
+}
+
+// done reports whether every page was fetched.
+func (p *pager[T]) done() bool {
+	return p.pageUrl == ""
+}
+
+// next fetches the next page. A *PartialDecodeError is returned alongside the
+// valid items of the page in lenient decoding mode, with indices relative to
+// the page. On any other error, the pager stops.
+func (p *pager[T]) next(ctx context.Context) ([]T, error) {
+	pageUrl := p.pageUrl
+
+	u, err := url.Parse(pageUrl)
+	if err != nil {
+		p.pageUrl = ""
+		return nil, err
+	}
+	if !u.IsAbs() {
+		// Relative to the first page, as a browser would resolve it
+		u = p.first.ResolveReference(u)
+		pageUrl = u.String()
+	}
+	if u.Host != p.first.Host {
+		p.pageUrl = ""
+		return nil, fmt.Errorf("[%s] Refusing to follow next page URL to another host: %s", p.op, u.Host)
+	}
+	if u.Scheme != p.first.Scheme {
+		p.pageUrl = ""
+		return nil, fmt.Errorf("[%s] Refusing to follow next page URL with another scheme: %s", p.op, u.Scheme)
+	}
+	missing := url.Values{}
+	for k := range p.sticky {
+		if !u.Query().Has(k) {
+			missing.Set(k, p.sticky.Get(k))
+		}
+	}
+	if len(missing) > 0 {
+		sep := "&"
+		if u.RawQuery == "" {
+			sep = "?"
+		}
+		pageUrl = fmt.Sprintf("%s%s%s", pageUrl, sep, missing.Encode())
+	}
+
+	if p.fetched[pageUrl] {
+		p.pageUrl = ""
+		return nil, fmt.Errorf("[%s] Next page URL loops back to a page already fetched: %s", p.op, pageUrl)
+	}
+	p.fetched[pageUrl] = true
+
+	items, meta, err := fetchPage[T](ctx, p.c, p.op, pageUrl)
+
+	var pageErr *PartialDecodeError
+	if err != nil && !errors.As(err, &pageErr) {
+		p.pageUrl = ""
+		return nil, err
+	}
+
+	p.pageUrl = meta.NextPageUrl
+	return items, err
+}
@@ -0,0 +1,317 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDataList_Array(t *testing.T) {
+	var resp getBadgesResponse
+	err := json.Unmarshal([]byte(`{"data": [{"id": "badge-123"}, {"id": "badge-456"}]}`), &resp)
+
+	assert.NoError(t, err)
+	assert.Equal(t, dataList[BadgeInfo]{{Id: "badge-123"}, {Id: "badge-456"}}, resp.Data)
+}
+
+func TestDataList_Object(t *testing.T) {
+	var resp getBadgesResponse
+	err := json.Unmarshal([]byte(`{"data": {"id": "badge-123"}}`), &resp)
+
+	assert.NoError(t, err)
+	assert.Equal(t, dataList[BadgeInfo]{{Id: "badge-123"}}, resp.Data)
+}
+
+func TestDataList_Null(t *testing.T) {
+	var resp getBadgeTemplatesResponse
+	err := json.Unmarshal([]byte(`{"data": null}`), &resp)
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Data)
+}
+
+func TestDataList_Invalid(t *testing.T) {
+	var resp getBadgeTemplatesResponse
+	err := json.Unmarshal([]byte(`{"data": "not-a-template"}`), &resp)
+
+	assert.Error(t, err)
+}
+
+func TestDecodeResponse(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "badge-123"}}`)),
+	}
+
+	b, err := decodeResponse[BadgeInfo]("credly.Test", resp, http.StatusOK)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "badge-123", b.Id)
+}
+
+func TestDecodeResponse_UnexpectedStatus(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"errors": [{"attribute": "recipient_email", "message": "is invalid"}]}`)),
+	}
+
+	_, err := decodeResponse[BadgeInfo]("credly.Test", resp, http.StatusCreated)
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Equal(t, []FieldError{{Field: "recipient_email", Message: "is invalid"}}, apiErr.FieldErrors)
+}
+
+func TestDecodeResponse_ErrorPayload(t *testing.T) {
+	// An errors array in place of the data is a failure, whatever the status
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": null, "errors": [{"source": {"parameter": "id"}, "detail": "is malformed"}]}`)),
+	}
+
+	_, err := decodeResponse[BadgeInfo]("credly.Test", resp, http.StatusOK)
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "is malformed", apiErr.Message)
+	assert.Equal(t, []FieldError{{Field: "id", Message: "is malformed"}}, apiErr.FieldErrors)
+
+	// Invalid JSON
+	resp.Body = io.NopCloser(bytes.NewBufferString(`{"data": `))
+	_, err = decodeResponse[BadgeInfo]("credly.Test", resp, http.StatusOK)
+	assert.ErrorContains(t, err, "[credly.Test] Failed to parse JSON data")
+}
+
+func TestDecodeResponse_NotJSON(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("<html>Maintenance</html>")),
+	}
+
+	_, err := decodeResponse[BadgeInfo]("credly.GetBadgeById", resp, http.StatusOK)
+
+	assert.ErrorContains(t, err, "Failed to parse JSON data")
+	assert.ErrorContains(t, err, "(body: <html>Maintenance</html>)")
+}
+
+func TestWithPageSize(t *testing.T) {
+	assert.Equal(t, 25, NewClient("test-token", "org-123", WithPageSize(25)).pageSize)
+	assert.Equal(t, 100, NewClient("test-token", "org-123", WithPageSize(1000)).pageSize)
+	assert.Zero(t, NewClient("test-token", "org-123", WithPageSize(-1)).pageSize)
+
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithPageSize(1000), WithHTTPClient(mockClient))
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-123"}},
+		"metadata": PageMetadata{NextPageUrl: "https://api.credly.com/v1/organizations/org-123/badges?page=2"},
+	})
+
+	// Every page is requested with the page size
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("page[size]") == "100" && req.URL.Query().Get("page") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("page[size]") == "100" && req.URL.Query().Get("page") == "2"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "badge-456"}]}`)),
+	}, nil).Once()
+
+	badges, err := client.GetBadges("test@example.com", nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, badges, 2)
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/organizations/org-123/badge_templates" && req.URL.Query().Get("page[size]") == "100"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": []}`)),
+	}, nil).Once()
+
+	_, err = client.GetBadgeTemplates()
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	// Explicit page sizes are kept
+	assert.Equal(t, "https://api.credly.com/v1/organizations/org-123/badges?page%5Bsize%5D=1", client.withPageSize("https://api.credly.com/v1/organizations/org-123/badges?page%5Bsize%5D=1"))
+}
+
+func TestListAll_FollowsNextPageUrlVerbatim(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	firstUrl := "https://api.credly.com/v1/organizations/org-123/badges?filter=state%3A%3Aaccepted"
+	nextUrl := "https://api.credly.com/v1/organizations/org-123/badges?cursor=eyJpZCI6IjEyMyJ9%2F%2B%3D%3D&filter=state%3A%3Aaccepted"
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-123"}},
+		"metadata": map[string]string{"next_page_url": nextUrl},
+	})
+	page2, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-456"}},
+		"metadata": map[string]interface{}{"next_page_url": nil},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == firstUrl
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == nextUrl && req.Header.Get("Authorization") != ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page2)),
+	}, nil).Once()
+
+	badges, err := listAll[BadgeInfo](context.Background(), client, "credly.test", firstUrl)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeInfo{{Id: "badge-123"}, {Id: "badge-456"}}, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestListAll_RefusesOtherHost(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-123"}},
+		"metadata": map[string]string{"next_page_url": "https://evil.example.com/badges?page=2"},
+	})
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+
+	badges, err := listAll[BadgeInfo](context.Background(), client, "credly.test", "https://api.credly.com/v1/organizations/org-123/badges")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "another host")
+	assert.Empty(t, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestListAll_RefusesOtherScheme(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-123"}},
+		"metadata": map[string]string{"next_page_url": "http://api.credly.com/v1/organizations/org-123/badges?page=2"},
+	})
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+
+	badges, err := listAll[BadgeInfo](context.Background(), client, "credly.test", "https://api.credly.com/v1/organizations/org-123/badges")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "another scheme")
+	assert.Empty(t, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestListAll_ResolvesRelativeNextPageUrl(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-123"}},
+		"metadata": map[string]string{"next_page_url": "/v1/organizations/org-123/badges?page=2"},
+	})
+	page2, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-456"}},
+		"metadata": map[string]interface{}{"next_page_url": nil},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.RawQuery == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://api.credly.com/v1/organizations/org-123/badges?page=2"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page2)),
+	}, nil).Once()
+
+	badges, err := listAll[BadgeInfo](context.Background(), client, "credly.test", "https://api.credly.com/v1/organizations/org-123/badges")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BadgeInfo{{Id: "badge-123"}, {Id: "badge-456"}}, badges)
+	mockClient.AssertExpectations(t)
+}
+
+func TestListAll_StopsOnNextPageLoop(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	firstUrl := "https://api.credly.com/v1/organizations/org-123/badges?page=1"
+	nextUrl := "https://api.credly.com/v1/organizations/org-123/badges?page=2"
+
+	page1, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-123"}},
+		"metadata": map[string]string{"next_page_url": nextUrl},
+	})
+	page2, _ := json.Marshal(map[string]interface{}{
+		"data":     []BadgeInfo{{Id: "badge-456"}},
+		"metadata": map[string]string{"next_page_url": firstUrl},
+	})
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == firstUrl
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page1)),
+	}, nil).Once()
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == nextUrl
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(page2)),
+	}, nil).Once()
+
+	_, err := listAll[BadgeInfo](context.Background(), client, "credly.test", firstUrl)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already fetched")
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,240 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// defaultRetryBaseDelay is the default value of Client.RetryBaseDelay.
+	defaultRetryBaseDelay = 500 * time.Millisecond
+
+	// maxRetryDelay caps the delay between two attempts.
+	maxRetryDelay = 30 * time.Second
+)
+
+// RetryPolicy decides whether a request is retried after an attempt, given
+// either its response or the error returned by the HTTP client. The request is
+// available in resp.Request, and the context is checked by the Client before
+// retrying, see WithRetryPolicy.
+type RetryPolicy func(resp *http.Response, err error) bool
+
+// DefaultRetryPolicy retries requests that Credly rejected with 429 or a 5xx
+// status code, and requests that failed with a transient network error, see
+// IsTransientError.
+//
+// A non-idempotent request, such as the POST issuing a badge, that failed with
+// a 5xx status code, timed out or whose connection was reset may have been
+// processed by Credly, e.g. and issued the badge. It is only retried if it
+// carries an Idempotency-Key header (for statuses), Credly rejected it with 429,
+// or the connection couldn't be established.
+func DefaultRetryPolicy(resp *http.Response, err error) bool {
+	if err != nil {
+		return IsTransientError(err) && (isDialError(err) || !isPostError(err))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return retryableStatus(resp.StatusCode) && isIdempotent(resp.Request)
+}
+
+// isIdempotent reports whether req can be sent again without risking applying
+// it twice, because of its method or its Idempotency-Key header. A nil request
+// is not.
+func isIdempotent(req *http.Request) bool {
+	if req == nil {
+		return false
+	}
+	if req.Header.Get(idempotencyKeyHeader) != "" {
+		return true
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// IsTransientError reports whether err is a network error that may not occur
+// again, such as a timeout, a reset connection or a failed DNS lookup, for use
+// in a RetryPolicy. Cancelled contexts and permanent errors, such as a
+// malformed URL or an unknown host, are not transient.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	if isDialError(err) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isDialError reports whether err occurred while connecting to the server, so
+// that the request wasn't sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isPostError reports whether err was returned by an http.Client for a POST
+// request, which it records in the Op of the *url.Error.
+func isPostError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && strings.EqualFold(urlErr.Op, http.MethodPost)
+}
+
+// WithRetryPolicy sets the policy deciding which failed requests are retried,
+// with the backoff and the number of retries set by RetryBaseDelay and
+// MaxRetries. Defaults to DefaultRetryPolicy.
+//
+// For example, to retry transport errors and only retry writes on 429:
+//
+//	credly.WithRetryPolicy(func(resp *http.Response, err error) bool {
+//		if err != nil {
+//			return true
+//		}
+//		if resp.Request.Method != http.MethodGet {
+//			return resp.StatusCode == http.StatusTooManyRequests
+//		}
+//		return credly.DefaultRetryPolicy(resp, err)
+//	})
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// shouldRetry reports whether the request is retried after an attempt, according to the retry policy.
+func (c *Client) shouldRetry(resp *http.Response, err error) bool {
+	if c.retryPolicy == nil {
+		return DefaultRetryPolicy(resp, err)
+	}
+	return c.retryPolicy(resp, err)
+}
+
+// sendWithRetry sends the request, retrying it up to MaxRetries times when the
+// retry policy allows it, by default when Credly responds with 429 or a 5xx
+// status code or on a transient network error.
+//
+// The request body is replayed using req.GetBody, which Do sets if needed.
+// Requests whose body can't be replayed, e.g. because a middleware replaced it,
+// are not retried.
+func (c *Client) sendWithRetry(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.send(req)
+		if err == nil && resp != nil && resp.Request == nil {
+			resp.Request = req
+		}
+		if attempt >= c.MaxRetries || req.Context().Err() != nil || !c.shouldRetry(resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err
+		}
+
+		delay := c.retryDelay(attempt, resp)
+
+		if err == nil {
+			// Drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryableStatus reports whether a response with the given status code should be retried.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// retryDelay returns how long to wait before the retry following attempt.
+// It honors the Retry-After header, and otherwise uses exponential backoff
+// with jitter.
+func (c *Client) retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); d > 0 {
+			return min(d, maxRetryDelay)
+		}
+	}
+
+	base := c.RetryBaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+
+	d := base << attempt
+	if d <= 0 || d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+
+	// Pick a random delay in [d/2, d) so that concurrent clients don't retry in lockstep
+	half := d / 2
+	return half + rand.N(d-half)
+}
+
+// sleep waits for d, returning early with the context error if ctx is done.
+// If ctx has a deadline before d elapses, it returns context.DeadlineExceeded
+// right away rather than waiting for the deadline to fail anyway.
+func sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return fmt.Errorf("[credly.Do] Waiting %v would exceed the context deadline: %w", d, context.DeadlineExceeded)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,405 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRetry_TooManyRequestsThenCreated(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		MaxRetries:     3,
+		RetryBaseDelay: time.Millisecond,
+	}
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{
+		Data: BadgeInfo{Id: "badge-123"},
+	})
+
+	// The POST body must be sent in full on every attempt
+	var bodies []string
+	recordBody := func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+	}
+
+	mockClient.On("Do", mock.Anything).Run(recordBody).Return(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+	mockClient.On("Do", mock.Anything).Run(recordBody).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil).Once()
+
+	badge, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "badge-123", badge.Id)
+	assert.Len(t, bodies, 2)
+	assert.Contains(t, bodies[0], "test@example.com")
+	assert.Equal(t, bodies[0], bodies[1])
+	mockClient.AssertExpectations(t)
+}
+
+func TestRetry_GivesUpAfterMaxRetries(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		MaxRetries:     2,
+		RetryBaseDelay: time.Millisecond,
+	}
+
+	for i := 0; i < 3; i++ {
+		mockClient.On("Do", mock.Anything).Return(&http.Response{
+			StatusCode: http.StatusBadGateway,
+			Body:       io.NopCloser(bytes.NewBufferString("")),
+		}, nil).Once()
+	}
+
+	_, err := client.GetBadgeTemplates()
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	mockClient.AssertNumberOfCalls(t, "Do", 3)
+}
+
+func TestRetry_Disabled(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil)
+
+	_, err := client.GetBadgeTemplates()
+
+	assert.Error(t, err)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestRetry_DisabledByDefault(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithHTTPClient(mockClient))
+
+	// Credly may have issued the badge before failing, so it isn't sent again
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil)
+
+	_, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+	assert.Error(t, err)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestRetry_NotOnClientErrors(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, MaxRetries: 3, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil)
+
+	_, err := client.GetBadgeTemplate("template-123")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	errReset := errors.New("connection reset by peer")
+
+	// Retry transport errors, and writes on 429 only
+	policy := func(resp *http.Response, err error) bool {
+		if err != nil {
+			return true
+		}
+		if resp.Request.Method != http.MethodGet {
+			return resp.StatusCode == http.StatusTooManyRequests
+		}
+		return DefaultRetryPolicy(resp, err)
+	}
+
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithRetryPolicy(policy))
+	client.HTTPClient = mockClient
+	client.MaxRetries = 3
+	client.RetryBaseDelay = time.Millisecond
+
+	mockClient.On("Do", mock.Anything).Return((*http.Response)(nil), errReset).Once()
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "template-123"}}`)),
+	}, nil).Once()
+
+	_, err := client.GetBadgeTemplate("template-123")
+
+	assert.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "Do", 2)
+
+	// A 500 on IssueBadge isn't retried
+	mockClient = new(MockHTTPClient)
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	_, err = client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+	assert.Error(t, err)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	assert.True(t, DefaultRetryPolicy(&http.Response{StatusCode: http.StatusTooManyRequests}, nil))
+	assert.True(t, DefaultRetryPolicy(&http.Response{StatusCode: http.StatusBadGateway, Request: httptest.NewRequest("GET", "/", nil)}, nil))
+	assert.False(t, DefaultRetryPolicy(&http.Response{StatusCode: http.StatusNotFound}, nil))
+	assert.False(t, DefaultRetryPolicy(nil, errors.New("connection reset by peer")))
+
+	dial := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	assert.True(t, DefaultRetryPolicy(nil, &url.Error{Op: "Get", Err: reset}))
+	assert.True(t, DefaultRetryPolicy(nil, &url.Error{Op: "Post", Err: dial}))
+	// A POST request may have reached Credly before the connection was reset
+	assert.False(t, DefaultRetryPolicy(nil, &url.Error{Op: "Post", Err: reset}))
+}
+
+func TestDefaultRetryPolicy_NonIdempotent(t *testing.T) {
+	post := httptest.NewRequest("POST", "/", nil)
+	keyed := httptest.NewRequest("POST", "/", nil)
+	keyed.Header.Set("Idempotency-Key", "key-123")
+
+	// Credly may have processed a POST that failed with a 5xx status code
+	assert.False(t, DefaultRetryPolicy(&http.Response{StatusCode: http.StatusServiceUnavailable, Request: post}, nil))
+	assert.False(t, DefaultRetryPolicy(&http.Response{StatusCode: http.StatusBadGateway}, nil))
+	assert.True(t, DefaultRetryPolicy(&http.Response{StatusCode: http.StatusServiceUnavailable, Request: keyed}, nil))
+	assert.True(t, DefaultRetryPolicy(&http.Response{StatusCode: http.StatusTooManyRequests, Request: post}, nil))
+	assert.True(t, DefaultRetryPolicy(&http.Response{StatusCode: http.StatusServiceUnavailable, Request: httptest.NewRequest("PUT", "/", nil)}, nil))
+
+	// With retries enabled, IssueBadge is only resent when it carries an idempotency key
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123", MaxRetries: 3, RetryBaseDelay: time.Millisecond}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	_, err := client.IssueBadge("template-123", "test@example.com", "John", "Doe")
+
+	assert.Error(t, err)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+
+	responseBody, _ := json.Marshal(issueBadgeResponse{Data: BadgeInfo{Id: "badge-123"}})
+	mockClient = new(MockHTTPClient)
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil).Once()
+
+	badge, err := client.IssueBadgeWithOptions("template-123", "test@example.com", "John", "Doe", IssueOptions{IdempotencyKey: "key-123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "badge-123", badge.Id)
+	mockClient.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{name: "nil", err: nil, transient: false},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, transient: true},
+		{name: "connection reset", err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, transient: true},
+		{name: "unexpected EOF", err: &url.Error{Op: "Get", Err: io.ErrUnexpectedEOF}, transient: true},
+		{name: "DNS timeout", err: &net.OpError{Op: "dial", Err: &net.DNSError{Name: "api.credly.com", IsTimeout: true}}, transient: true},
+		{name: "unknown host", err: &net.OpError{Op: "dial", Err: &net.DNSError{Name: "api.credly.invalid", IsNotFound: true}}, transient: false},
+		{name: "timeout", err: &url.Error{Op: "Get", Err: os.ErrDeadlineExceeded}, transient: true},
+		{name: "context canceled", err: &url.Error{Op: "Get", Err: context.Canceled}, transient: false},
+		{name: "malformed URL", err: &url.Error{Op: "parse", Err: errors.New("invalid control character in URL")}, transient: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.transient, IsTransientError(tt.err))
+		})
+	}
+}
+
+func TestRetry_TransientNetworkError(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		MaxRetries:     2,
+		RetryBaseDelay: time.Millisecond,
+	}
+
+	mockClient.On("Do", mock.Anything).Return((*http.Response)(nil), &url.Error{
+		Op:  "Get",
+		Err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET},
+	}).Once()
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": {"id": "template-123"}}`)),
+	}, nil).Once()
+
+	_, err := client.GetBadgeTemplate("template-123")
+
+	assert.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := &Client{RetryBaseDelay: 100 * time.Millisecond, OrganizationId: "org-123"}
+	resp := &http.Response{Header: http.Header{}}
+
+	for attempt, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		d := client.retryDelay(attempt, resp)
+		assert.GreaterOrEqual(t, d, max/2)
+		assert.Less(t, d, max)
+	}
+
+	assert.LessOrEqual(t, client.retryDelay(20, resp), maxRetryDelay)
+
+	// Retry-After takes precedence over the backoff
+	resp.Header.Set("Retry-After", "7")
+	assert.Equal(t, 7*time.Second, client.retryDelay(0, resp))
+}
+
+func TestSleep_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := sleep(ctx, time.Minute)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestSleep_DeadlineTooSoon(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := sleep(ctx, time.Minute)
+
+	// Fails right away instead of waiting for the deadline
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	assert.NoError(t, sleep(ctx, time.Millisecond))
+}
+
+func TestRetry_CanceledDuringBackoff(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		MaxRetries:     3,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockClient.On("Do", mock.Anything).Run(func(mock.Arguments) {
+		time.AfterFunc(10*time.Millisecond, cancel)
+	}).Return(&http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Retry-After": []string{"30"}},
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	start := time.Now()
+	_, err := client.GetBadgeContext(ctx, "jane@example.com", "template-123")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+	mockClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestRetry_BuffersNonReplayableBody(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		OrganizationId: "org-123",
+		HTTPClient:     mockClient,
+		MaxRetries:     1,
+		RetryBaseDelay: time.Millisecond,
+	}
+
+	var bodies []string
+	recordBody := func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+	}
+
+	mockClient.On("Do", mock.Anything).Run(recordBody).Return(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+	mockClient.On("Do", mock.Anything).Run(recordBody).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	// io.NopCloser hides the type of the reader, so http.NewRequest can't set GetBody
+	req, err := http.NewRequest("POST", "https://api.credly.com/v1/some-endpoint", io.NopCloser(bytes.NewBufferString(`{"a": 1}`)))
+	assert.NoError(t, err)
+	assert.Nil(t, req.GetBody)
+
+	resp, err := client.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{`{"a": 1}`, `{"a": 1}`}, bodies)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,263 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Intervals supported by GetIssuanceTimeSeries.
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// IssuanceBucket holds the number of badges issued during one interval of a time series.
+type IssuanceBucket struct {
+	// Start is the beginning of the interval, in UTC.
+	Start time.Time
+
+	// Count is the number of badges issued during the interval.
+	Count int
+}
+
+// GetIssuanceTimeSeries returns the number of badges issued by the organization over time.
+// See GetIssuanceTimeSeriesContext.
+func (c *Client) GetIssuanceTimeSeries(start, end time.Time, interval string) ([]IssuanceBucket, error) {
+	return c.GetIssuanceTimeSeriesContext(context.Background(), start, end, interval)
+}
+
+// GetIssuanceTimeSeriesContext returns the number of badges issued by the
+// organization between start and end, bucketed by day, week (starting on
+// Monday) or month in UTC. Every interval in the range has a bucket, including
+// empty ones.
+//
+// Credly doesn't provide a time-series endpoint, so the counts are computed
+// client-side by paging through every badge issued in the range: this costs one
+// request per page of badges and can be slow for busy organizations.
+//
+// ctx: The context controlling the requests.
+// start: The beginning of the range, must not be zero.
+// end: The end of the range, must not be zero.
+// interval: The bucket size, one of IntervalDay, IntervalWeek or IntervalMonth.
+// Returns: The buckets in chronological order, or an error if the operation fails.
+func (c *Client) GetIssuanceTimeSeriesContext(ctx context.Context, start, end time.Time, interval string) ([]IssuanceBucket, error) {
+	if _, ok := intervalSteps[interval]; !ok {
+		return nil, fmt.Errorf("[credly.GetIssuanceTimeSeries] Unsupported interval: %q", interval)
+	}
+	// A zero bound would be dropped from the filter, and a zero start would
+	// allocate a bucket per interval since year 1
+	if start.IsZero() || end.IsZero() {
+		return nil, fmt.Errorf("[credly.GetIssuanceTimeSeries] Start and end must not be zero")
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("[credly.GetIssuanceTimeSeries] Start must not be after end")
+	}
+	start, end = start.UTC(), end.UTC()
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{IssuedAfter: start, IssuedBefore: end})
+	if err != nil {
+		return nil, err
+	}
+
+	badges, err := listAll[BadgeInfo](ctx, c, "credly.GetIssuanceTimeSeries", qUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []IssuanceBucket
+	index := make(map[time.Time]int)
+	for t := truncateInterval(start, interval); !t.After(end); t = nextInterval(t, interval) {
+		index[t] = len(buckets)
+		buckets = append(buckets, IssuanceBucket{Start: t})
+	}
+
+	for _, b := range badges {
+		issuedAt := b.IssuedAt.UTC()
+		if issuedAt.Before(start) || issuedAt.After(end) {
+			continue
+		}
+		if i, ok := index[truncateInterval(issuedAt, interval)]; ok {
+			buckets[i].Count++
+		}
+	}
+
+	return buckets, nil
+}
+
+// BadgeTemplateStats holds the number of badges issued from a badge template.
+type BadgeTemplateStats struct {
+	// Issued is the number of badges issued from the template, in any state.
+	Issued int
+
+	// Accepted is the number of badges accepted by their recipients.
+	Accepted int
+}
+
+// GetBadgeTemplateStats returns the number of badges issued from a badge template.
+// See GetBadgeTemplateStatsContext.
+func (c *Client) GetBadgeTemplateStats(templateId string) (BadgeTemplateStats, error) {
+	return c.GetBadgeTemplateStatsContext(context.Background(), templateId)
+}
+
+// GetBadgeTemplateStatsContext returns the number of badges issued from a badge
+// template and the number of them accepted, e.g. to rank certifications.
+//
+// Credly doesn't expose these counts on templates, so they are read from the
+// total count of two filtered badge listings, fetching a single badge each.
+//
+// ctx: The context controlling the requests.
+// templateId: The ID of the badge template.
+// Returns: The BadgeTemplateStats, or an error if the operation fails.
+func (c *Client) GetBadgeTemplateStatsContext(ctx context.Context, templateId string) (BadgeTemplateStats, error) {
+	if err := checkNotEmpty("credly.GetBadgeTemplateStats", "Template ID", templateId); err != nil {
+		return BadgeTemplateStats{}, err
+	}
+
+	var stats BadgeTemplateStats
+	var err error
+
+	filter := BadgeFilter{TemplateId: templateId}
+	if stats.Issued, err = c.countBadges(ctx, "credly.GetBadgeTemplateStats", filter); err != nil {
+		return BadgeTemplateStats{}, err
+	}
+
+	filter.State = BadgeStateAccepted
+	if stats.Accepted, err = c.countBadges(ctx, "credly.GetBadgeTemplateStats", filter); err != nil {
+		return BadgeTemplateStats{}, err
+	}
+
+	return stats, nil
+}
+
+// TemplateBreakdown holds the number of badges issued from a badge template, by state.
+type TemplateBreakdown struct {
+	// Issued is the number of badges issued from the template, in any state.
+	Issued int
+
+	// States maps each badge state, e.g. BadgeStateAccepted, to the number of
+	// the template's badges in that state. States without badges are omitted.
+	States map[string]int
+}
+
+// GetBadgeTemplateBreakdown returns the number of badges issued from a badge
+// template, by state. See GetBadgeTemplateBreakdownContext.
+func (c *Client) GetBadgeTemplateBreakdown(templateId string) (TemplateBreakdown, error) {
+	return c.GetBadgeTemplateBreakdownContext(context.Background(), templateId)
+}
+
+// GetBadgeTemplateBreakdownContext returns the number of badges issued from a
+// badge template and how many of them are in each state, e.g. pending,
+// accepted or revoked, for a certification dashboard.
+//
+// Credly doesn't provide aggregate counts by state, so they are tallied
+// client-side by paging through every badge of the template: this costs one
+// request per page of badges. GetBadgeTemplateStatsContext is cheaper when
+// only the issued and accepted counts are needed.
+//
+// ctx: The context controlling the requests.
+// templateId: The ID of the badge template.
+// Returns: The TemplateBreakdown, or an error if the operation fails.
+func (c *Client) GetBadgeTemplateBreakdownContext(ctx context.Context, templateId string) (TemplateBreakdown, error) {
+	if err := checkNotEmpty("credly.GetBadgeTemplateBreakdown", "Template ID", templateId); err != nil {
+		return TemplateBreakdown{}, err
+	}
+
+	qUrl, err := c.filteredBadgesUrl(BadgeFilter{TemplateId: templateId})
+	if err != nil {
+		return TemplateBreakdown{}, err
+	}
+
+	badges, err := listAll[BadgeInfo](ctx, c, "credly.GetBadgeTemplateBreakdown", qUrl)
+	if err != nil {
+		return TemplateBreakdown{}, err
+	}
+
+	breakdown := TemplateBreakdown{Issued: len(badges), States: map[string]int{}}
+	for _, b := range badges {
+		breakdown.States[b.State]++
+	}
+
+	return breakdown, nil
+}
+
+// CountBadges returns the number of the organization's badges matching filter.
+// See CountBadgesContext.
+func (c *Client) CountBadges(filter BadgeFilter) (int, error) {
+	return c.CountBadgesContext(context.Background(), filter)
+}
+
+// CountBadgesContext returns the number of the organization's badges matching
+// filter, e.g. to size an export before running it. A single badge is
+// requested and the count is read from the pagination metadata, so this costs
+// one request regardless of the number of badges.
+//
+// ctx: The context controlling the request.
+// filter: The criteria the badges must match.
+// Returns: The number of matching badges, or an error if the filter is invalid or the operation fails.
+func (c *Client) CountBadgesContext(ctx context.Context, filter BadgeFilter) (int, error) {
+	return c.countBadges(ctx, "credly.CountBadges", filter)
+}
+
+// countBadges returns the number of the organization's badges matching filter,
+// as reported in the pagination metadata.
+func (c *Client) countBadges(ctx context.Context, op string, filter BadgeFilter) (int, error) {
+	qUrl, err := c.filteredBadgesUrl(filter)
+	if err != nil {
+		return 0, err
+	}
+	qUrl = withQueryParam(qUrl, pageSizeParam, "1")
+
+	items, meta, err := fetchPage[json.RawMessage](ctx, c, op, qUrl)
+	if err != nil {
+		return 0, err
+	}
+	if meta.TotalCount == 0 && len(items) > 0 {
+		return 0, fmt.Errorf("[%s] Response is missing the total count", op)
+	}
+
+	return meta.TotalCount, nil
+}
+
+// intervalSteps maps each supported interval to its length as (years, months, days).
+var intervalSteps = map[string][3]int{
+	IntervalDay:   {0, 0, 1},
+	IntervalWeek:  {0, 0, 7},
+	IntervalMonth: {0, 1, 0},
+}
+
+// truncateInterval returns the beginning of the interval containing t, which must be in UTC.
+func truncateInterval(t time.Time, interval string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch interval {
+	case IntervalWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// nextInterval returns the beginning of the interval following the one starting at t.
+func nextInterval(t time.Time, interval string) time.Time {
+	step := intervalSteps[interval]
+	return t.AddDate(step[0], step[1], step[2])
+}
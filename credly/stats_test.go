@@ -0,0 +1,214 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetIssuanceTimeSeries(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+	}
+
+	page1 := `{"data": [{"id": "b1", "issued_at": "2024-03-01T10:00:00Z"}, {"id": "b2", "issued_at": "2024-03-01T23:30:00Z"}],
+		"metadata": {"next_page_url": "https://api.credly.com/v1/organizations/org-123/badges?page=2"}}`
+	page2 := `{"data": [{"id": "b3", "issued_at": "2024-03-03T08:00:00+02:00"}], "metadata": {"next_page_url": null}}`
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "issued_at_min::2024-03-01T00:00:00Z|issued_at_max::2024-03-03T23:59:59Z"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(page1)),
+	}, nil).Once()
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("page") == "2"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(page2)),
+	}, nil).Once()
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 3, 23, 59, 59, 0, time.UTC)
+
+	buckets, err := client.GetIssuanceTimeSeries(start, end, IntervalDay)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []IssuanceBucket{
+		{Start: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Count: 2},
+		{Start: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Count: 0},
+		{Start: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), Count: 1},
+	}, buckets)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetIssuanceTimeSeries_InvalidParameters(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := client.GetIssuanceTimeSeries(start, start.AddDate(0, 1, 0), "year")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported interval")
+
+	_, err = client.GetIssuanceTimeSeries(start, start.AddDate(0, 0, -1), IntervalDay)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Start must not be after end")
+
+	_, err = client.GetIssuanceTimeSeries(time.Time{}, start, IntervalDay)
+	assert.ErrorContains(t, err, "Start and end must not be zero")
+
+	_, err = client.GetIssuanceTimeSeries(start, time.Time{}, IntervalDay)
+	assert.ErrorContains(t, err, "Start and end must not be zero")
+
+	mockClient.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestTruncateInterval(t *testing.T) {
+	// Wednesday
+	ts := time.Date(2024, 3, 13, 15, 4, 5, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC), truncateInterval(ts, IntervalDay))
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), truncateInterval(ts, IntervalWeek))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), truncateInterval(ts, IntervalMonth))
+
+	// Sunday belongs to the week starting the previous Monday
+	sunday := time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), truncateInterval(sunday, IntervalWeek))
+}
+
+func TestGetBadgeTemplateStats(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "badge_template_id::template-123" && req.URL.Query().Get("page[size]") == "1"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "b1"}], "metadata": {"total_count": 42}}`)),
+	}, nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "badge_template_id::template-123|state::accepted"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "b1"}], "metadata": {"total_count": 30}}`)),
+	}, nil).Once()
+
+	stats, err := client.GetBadgeTemplateStats("template-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, BadgeTemplateStats{Issued: 42, Accepted: 30}, stats)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgeTemplateStats_NoBadges(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	for i := 0; i < 2; i++ {
+		mockClient.On("Do", mock.Anything).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"data": [], "metadata": {"total_count": 0}}`)),
+		}, nil).Once()
+	}
+
+	stats, err := client.GetBadgeTemplateStats("template-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, BadgeTemplateStats{}, stats)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBadgeTemplateStats_MissingTotalCount(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "b1"}]}`)),
+	}, nil).Once()
+
+	_, err := client.GetBadgeTemplateStats("template-123")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing the total count")
+}
+
+func TestCountBadges(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+	WithPageSize(100)(client)
+
+	// A single badge is requested, whatever the configured page size
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "recipient_email_all::jane@example.com" && req.URL.Query().Get("page[size]") == "1"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "b1"}], "metadata": {"total_count": 7, "total_pages": 7}}`)),
+	}, nil).Once()
+
+	count, err := client.CountBadges(BadgeFilter{Email: "jane@example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, count)
+	mockClient.AssertExpectations(t)
+
+	_, err = client.CountBadges(BadgeFilter{State: "archived"})
+	assert.Error(t, err)
+}
+
+func TestGetBadgeTemplateBreakdown(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "badge_template_id::template-123" && req.URL.Query().Get("page") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body: io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "b1", "state": "accepted"}, {"id": "b2", "state": "pending"}],
+			"metadata": {"next_page_url": "https://api.credly.com/v1/organizations/org-123/badges?page=2"}}`)),
+	}, nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("filter") == "badge_template_id::template-123" && req.URL.Query().Get("page") == "2"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"data": [{"id": "b3", "state": "accepted"}, {"id": "b4", "state": "revoked"}]}`)),
+	}, nil).Once()
+
+	breakdown, err := client.GetBadgeTemplateBreakdown("template-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, TemplateBreakdown{
+		Issued: 4,
+		States: map[string]int{BadgeStateAccepted: 2, BadgeStatePending: 1, BadgeStateRevoked: 1},
+	}, breakdown)
+	mockClient.AssertExpectations(t)
+
+	_, err = client.GetBadgeTemplateBreakdown("")
+	assert.Error(t, err)
+}
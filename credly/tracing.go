@@ -0,0 +1,102 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer starts the spans tracing requests sent to Credly, see WithTracer.
+//
+// It is a subset of the OpenTelemetry tracing API, so that this package doesn't
+// depend on OpenTelemetry. An OpenTelemetry trace.Tracer is adapted with:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, credly.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetAttribute(key string, value interface{}) {
+//		s.Span.SetAttributes(attribute.String(key, fmt.Sprint(value)))
+//	}
+//
+//	func (s otelSpan) RecordError(err error) {
+//		s.Span.RecordError(err)
+//		s.Span.SetStatus(codes.Error, err.Error())
+//	}
+//
+//	func (s otelSpan) End() { s.Span.End() }
+type Tracer interface {
+	// Start starts a span named name as a child of the span in ctx, if any,
+	// and returns a context holding the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetAttribute sets an attribute of the span.
+	SetAttribute(key string, value interface{})
+
+	// RecordError records that the traced operation failed with err.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+}
+
+// WithTracer traces every request sent to Credly, including retries, with a
+// span named after the client method that sent it, e.g. "credly.IssueBadge",
+// or "credly.Do" for requests sent directly with Client.Do. Spans carry the
+// http.method, http.url and http.status_code attributes.
+// Nothing is traced by default.
+func WithTracer(t Tracer) Option {
+	return func(c *Client) {
+		c.tracer = t
+	}
+}
+
+// startSpan starts a span tracing req, returning req with the span's context.
+// The returned function ends the span with the outcome of the request.
+func (c *Client) startSpan(req *http.Request) (*http.Request, func(*http.Response, error)) {
+	if c.tracer == nil {
+		return req, func(*http.Response, error) {}
+	}
+
+	name := operation(req.Context())
+	if name == "" {
+		name = "credly.Do"
+	}
+
+	ctx, span := c.tracer.Start(req.Context(), name)
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.url", req.URL.String())
+
+	return req.WithContext(ctx), func(resp *http.Response, err error) {
+		if err != nil {
+			span.RecordError(err)
+		} else {
+			span.SetAttribute("http.status_code", resp.StatusCode)
+			if resp.StatusCode >= 400 {
+				span.RecordError(&APIError{Op: name, StatusCode: resp.StatusCode, RequestId: resp.Header.Get(requestIdHeader)})
+			}
+		}
+		span.End()
+	}
+}
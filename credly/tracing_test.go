@@ -0,0 +1,113 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testSpanKey is the context key holding the span started by testTracer.
+type testSpanKey struct{}
+
+// testTracer records the spans it starts.
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &testSpan{name: name, attributes: map[string]interface{}{}}
+	if parent, ok := ctx.Value(testSpanKey{}).(*testSpan); ok {
+		span.parent = parent
+	}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+// testSpan is a span started by testTracer.
+type testSpan struct {
+	name       string
+	parent     *testSpan
+	attributes map[string]interface{}
+	errs       []error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.errs = append(s.errs, err) }
+func (s *testSpan) End()                                       { s.ended = true }
+
+func TestWithTracer(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	tracer := &testTracer{}
+	client := NewClient("test-token", "org-123", WithTracer(tracer))
+	client.HTTPClient = mockClient
+
+	// The span is propagated to the HTTP client
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Context().Value(testSpanKey{}) != nil
+	})).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil)
+
+	parent := &testSpan{name: "handler"}
+	ctx := context.WithValue(context.Background(), testSpanKey{}, parent)
+
+	_, err := client.GetBadgeByIdContext(ctx, "badge-123")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.Equal(t, "credly.GetBadgeById", span.name)
+	assert.Same(t, parent, span.parent)
+	assert.Equal(t, map[string]interface{}{
+		"http.method":      "GET",
+		"http.url":         "https://api.credly.com/v1/organizations/org-123/badges/badge-123",
+		"http.status_code": http.StatusNotFound,
+	}, span.attributes)
+	assert.Len(t, span.errs, 1)
+	assert.ErrorIs(t, span.errs[0], ErrNotFound)
+	assert.True(t, span.ended)
+	mockClient.AssertExpectations(t)
+}
+
+func TestWithTracer_TransportError(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	tracer := &testTracer{}
+	client := NewClient("test-token", "org-123", WithTracer(tracer))
+	client.HTTPClient = mockClient
+
+	mockClient.On("Do", mock.Anything).Return((*http.Response)(nil), errors.New("connection refused"))
+
+	req, err := http.NewRequest("GET", "https://api.credly.com/v1/some-endpoint", nil)
+	assert.NoError(t, err)
+
+	_, err = client.Do(req)
+
+	assert.Error(t, err)
+	assert.Len(t, tracer.spans, 1)
+	assert.Equal(t, "credly.Do", tracer.spans[0].name)
+	assert.NotContains(t, tracer.spans[0].attributes, "http.status_code")
+	assert.EqualError(t, tracer.spans[0].errs[0], "connection refused")
+	assert.True(t, tracer.spans[0].ended)
+}
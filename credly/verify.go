@@ -0,0 +1,283 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// VerificationStatus is the outcome of verifying a claimed badge.
+type VerificationStatus string
+
+const (
+	// VerificationValid indicates the badge was issued by the organization and is still valid.
+	VerificationValid VerificationStatus = "valid"
+	// VerificationRevoked indicates the badge has been revoked.
+	VerificationRevoked VerificationStatus = "revoked"
+	// VerificationExpired indicates the badge is past its expiration date.
+	VerificationExpired VerificationStatus = "expired"
+	// VerificationNotFound indicates no such badge exists.
+	VerificationNotFound VerificationStatus = "not_found"
+	// VerificationOtherOrganization indicates the badge exists but was issued by another organization.
+	VerificationOtherOrganization VerificationStatus = "other_organization"
+	// VerificationError indicates the badge could not be verified; see BadgeVerification.Err.
+	VerificationError VerificationStatus = "error"
+)
+
+// BadgeVerification holds the result of verifying a single claimed badge.
+type BadgeVerification struct {
+	// BadgeId is the ID of the claimed badge.
+	BadgeId string
+
+	// Status is the outcome of the verification.
+	Status VerificationStatus
+
+	// Badge is the badge as returned by Credly, if it was found.
+	Badge BadgeInfo
+
+	// Err is the error that prevented verification when Status is VerificationError.
+	Err error
+}
+
+// VerifyBadges verifies a list of claimed badge IDs.
+// See VerifyBadgesContext.
+func (c *Client) VerifyBadges(ids []string) []BadgeVerification {
+	return c.VerifyBadgesContext(context.Background(), ids)
+}
+
+// VerifyBadgesContext verifies a list of claimed badge IDs, checking that each
+// badge exists, was issued by the Client's organization, and is neither revoked
+// nor expired. Badges are fetched concurrently with bounded parallelism.
+//
+// Badges are looked up among the organization's badges. Credly doesn't return
+// the badges of other organizations there, so those not found are looked up
+// with Credly's public endpoint, see VerifyBadge: badges found there are
+// reported as VerificationOtherOrganization, with the ID of their issuer in
+// Badge.Issuer.
+//
+// ctx: The context controlling the requests. Cancelling it aborts pending verifications.
+// ids: The IDs of the claimed badges.
+// Returns: One BadgeVerification per ID, in the same order as ids.
+func (c *Client) VerifyBadgesContext(ctx context.Context, ids []string) []BadgeVerification {
+	results := make([]BadgeVerification, len(ids))
+	now := time.Now()
+
+	forEach(ctx, len(ids), maxConcurrentRequests, func(ctx context.Context, i int) {
+		b, err := c.getBadgeById(ctx, ids[i])
+		v := c.verifyBadge(ids[i], b, err, now)
+		if v.Status == VerificationNotFound {
+			v = c.verifyOtherOrganization(ctx, v)
+		}
+		results[i] = v
+	})
+
+	return results
+}
+
+// verifyOtherOrganization looks up a badge that isn't one of the organization's
+// with the public endpoint, reporting it as issued by another organization if
+// it exists there.
+func (c *Client) verifyOtherOrganization(ctx context.Context, v BadgeVerification) BadgeVerification {
+	a, revoked, err := c.getBadgeAssertion(ctx, "credly.VerifyBadges", v.BadgeId)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return v
+	case err != nil:
+		v.Status = VerificationError
+		v.Err = err
+		return v
+	case a.Badge.Issuer.Id == c.OrganizationId:
+		// Not visible to the organization, e.g. deleted since
+		return v
+	}
+
+	v.Status = VerificationOtherOrganization
+	v.Badge = a.badgeInfo(v.BadgeId, revoked)
+	return v
+}
+
+// verifyBadge computes the verification result for a fetched badge.
+func (c *Client) verifyBadge(id string, b BadgeInfo, err error, now time.Time) BadgeVerification {
+	v := BadgeVerification{BadgeId: id, Badge: b}
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		v.Status = VerificationNotFound
+	case err != nil:
+		v.Status = VerificationError
+		v.Err = err
+	case b.State == BadgeStateRevoked:
+		v.Status = VerificationRevoked
+	case b.IsExpired(now):
+		v.Status = VerificationExpired
+	default:
+		v.Status = VerificationValid
+	}
+
+	return v
+}
+
+// publicKey is the context key marking requests to public endpoints.
+type publicKey struct{}
+
+// withPublic marks a request context as targeting a public endpoint, so that
+// the Client doesn't send its credentials.
+func withPublic(ctx context.Context) context.Context {
+	return context.WithValue(ctx, publicKey{}, true)
+}
+
+// isPublic reports whether the request context targets a public endpoint.
+func isPublic(ctx context.Context) bool {
+	public, _ := ctx.Value(publicKey{}).(bool)
+	return public
+}
+
+// badgeAssertion represents a badge in the Open Badges 2.0 format returned by
+// Credly's public verification endpoint.
+type badgeAssertion struct {
+	IssuedOn         time.Time     `json:"issuedOn"`
+	Expires          time.Time     `json:"expires"`
+	Revoked          bool          `json:"revoked"`
+	RevocationReason string        `json:"revocationReason"`
+	Badge            obiBadgeClass `json:"badge"`
+}
+
+// badgeInfo returns the BadgeInfo described by the assertion of badge id.
+func (a badgeAssertion) badgeInfo(id string, revoked bool) BadgeInfo {
+	b := BadgeInfo{
+		Id:        id,
+		IssuedAt:  a.IssuedOn,
+		ExpiresAt: a.Expires,
+		State:     BadgeStateAccepted,
+	}
+	b.Issuer.Id = a.Badge.Issuer.Id
+	if a.Revoked || revoked {
+		b.State = BadgeStateRevoked
+		b.RevocationReason = a.RevocationReason
+	}
+	return b
+}
+
+// obiBadgeClass is the badge class of an assertion. Credly embeds it along with
+// its issuer, but Open Badges also allows a URL referencing it, in which case
+// the issuer is unknown.
+type obiBadgeClass struct {
+	Issuer obiIssuer `json:"issuer"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *obiBadgeClass) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return nil
+	}
+
+	type badgeClass obiBadgeClass
+	return json.Unmarshal(data, (*badgeClass)(b))
+}
+
+// obiIssuer is the issuer of a badge class, given as an object or a URL such as
+// https://api.credly.com/v1/obi/v2/issuers/<organization ID>.
+type obiIssuer struct {
+	// Id is the ID of the issuing organization, the last segment of the issuer URL.
+	Id string
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (i *obiIssuer) UnmarshalJSON(data []byte) error {
+	var ref string
+	if err := json.Unmarshal(data, &ref); err != nil {
+		var obj struct {
+			Id string `json:"id"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		ref = obj.Id
+	}
+
+	if ref = strings.TrimRight(ref, "/"); ref != "" {
+		i.Id = path.Base(ref)
+	}
+	return nil
+}
+
+// getBadgeAssertion fetches the assertion of a badge from the public endpoint.
+// It also reports whether Credly answered 410 Gone, as Open Badges hosts do for
+// revoked assertions.
+func (c *Client) getBadgeAssertion(ctx context.Context, op, publicBadgeId string) (a badgeAssertion, gone bool, err error) {
+	qUrl := fmt.Sprintf("%s/obi/v2/badge_assertions/%s", c.baseURL(), url.PathEscape(publicBadgeId))
+
+	req, err := http.NewRequestWithContext(withPublic(withOperation(ctx, op)), "GET", qUrl, nil)
+	if err != nil {
+		return a, false, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return a, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusGone {
+		return a, false, newAPIError(op, resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&a); err != nil {
+		return a, false, fmt.Errorf("[%s] Failed to parse JSON data: %v", op, err)
+	}
+
+	return a, resp.StatusCode == http.StatusGone, nil
+}
+
+// VerifyBadge checks the authenticity of a badge from its public ID, as found
+// in its public URL, using Credly's public Open Badges endpoint. Unlike
+// VerifyBadges, it works for badges issued by any organization and doesn't
+// send the Client's credentials.
+//
+// As only accepted badges are public, the returned badge has State set to
+// BadgeStateAccepted, or BadgeStateRevoked for revoked badges.
+//
+// publicBadgeId: The public ID of the badge.
+// Returns: The badge if it is valid, the badge with ErrBadgeRevoked or ErrBadgeExpired if it isn't,
+// ErrNotFound if there's no such badge, or another error if the operation fails.
+func (c *Client) VerifyBadge(publicBadgeId string) (b BadgeInfo, err error) {
+	if publicBadgeId == "" {
+		return b, fmt.Errorf("[credly.VerifyBadge] Badge ID must not be empty")
+	}
+
+	a, gone, err := c.getBadgeAssertion(context.Background(), "credly.VerifyBadge", publicBadgeId)
+	if err != nil {
+		return b, err
+	}
+
+	b = a.badgeInfo(publicBadgeId, gone)
+
+	switch {
+	case b.State == BadgeStateRevoked:
+		return b, fmt.Errorf("[credly.VerifyBadge] %w: %s", ErrBadgeRevoked, publicBadgeId)
+	case b.IsExpired(time.Now()):
+		return b, fmt.Errorf("[credly.VerifyBadge] %w: %s", ErrBadgeExpired, publicBadgeId)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVerifyBadges(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := &Client{
+		HTTPClient:     mockClient,
+		authToken:      base64.StdEncoding.EncodeToString([]byte("test-token" + "|")),
+		OrganizationId: "org-123",
+	}
+
+	badges := map[string]BadgeInfo{
+		"valid":   {Id: "valid", State: "accepted"},
+		"revoked": {Id: "revoked", State: "revoked"},
+		"expired": {Id: "expired", State: "accepted", ExpiresAt: time.Now().Add(-time.Hour)},
+	}
+
+	for id, badge := range badges {
+		responseBody, _ := json.Marshal(getBadgeResponse{Data: badge})
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.Path == "/v1/organizations/org-123/badges/"+id
+		})).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(responseBody)),
+		}, nil).Once()
+	}
+
+	// Credly doesn't find the badges of other organizations either, they are
+	// looked up with the public endpoint
+	for _, id := range []string{"other", "missing"} {
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.Path == "/v1/organizations/org-123/badges/"+id
+		})).Return(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(bytes.NewBufferString("")),
+		}, nil).Once()
+	}
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/obi/v2/badge_assertions/other" && req.Header.Get("Authorization") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body: io.NopCloser(bytes.NewBufferString(`{
+			"type": "Assertion",
+			"badge": {"type": "BadgeClass", "issuer": {"id": "https://api.credly.com/v1/obi/v2/issuers/org-456", "name": "Other"}}
+		}`)),
+	}, nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/obi/v2/badge_assertions/missing"
+	})).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/v1/organizations/org-123/badges/broken"
+	})).Return(&http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	}, nil).Once()
+
+	results := client.VerifyBadges([]string{"valid", "revoked", "expired", "other", "missing", "broken"})
+
+	assert.Len(t, results, 6)
+	assert.Equal(t, VerificationValid, results[0].Status)
+	assert.Equal(t, "valid", results[0].Badge.Id)
+	assert.Equal(t, VerificationRevoked, results[1].Status)
+	assert.Equal(t, VerificationExpired, results[2].Status)
+	assert.Equal(t, VerificationOtherOrganization, results[3].Status)
+	assert.Equal(t, "org-456", results[3].Badge.Issuer.Id)
+	assert.Equal(t, VerificationNotFound, results[4].Status)
+	assert.Equal(t, "missing", results[4].BadgeId)
+	assert.Equal(t, VerificationError, results[5].Status)
+	assert.Error(t, results[5].Err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestVerifyBadge(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	client := NewClient("test-token", "org-123", WithHTTPClient(mockClient))
+
+	// The public endpoint is called without credentials
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://api.credly.com/v1/obi/v2/badge_assertions/public-123" &&
+			req.Header.Get("Authorization") == ""
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"type": "Assertion", "issuedOn": "2024-01-15T10:00:00Z"}`)),
+	}, nil).Once()
+
+	badge, err := client.VerifyBadge("public-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "public-123", badge.Id)
+	assert.Equal(t, BadgeStateAccepted, badge.State)
+	assert.True(t, time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC).Equal(badge.IssuedAt))
+	mockClient.AssertExpectations(t)
+}
+
+func TestVerifyBadge_Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		body   string
+		err    error
+	}{
+		{status: http.StatusGone, body: `{"revoked": true, "revocationReason": "Exam voided"}`, err: ErrBadgeRevoked},
+		{status: http.StatusOK, body: `{"revoked": true}`, err: ErrBadgeRevoked},
+		{status: http.StatusOK, body: `{"issuedOn": "2020-01-15T10:00:00Z", "expires": "2022-01-15T10:00:00Z"}`, err: ErrBadgeExpired},
+		{status: http.StatusNotFound, body: ``, err: ErrNotFound},
+	} {
+		mockClient := new(MockHTTPClient)
+		client := &Client{HTTPClient: mockClient, OrganizationId: "org-123"}
+
+		mockClient.On("Do", mock.Anything).Return(&http.Response{
+			StatusCode: tt.status,
+			Body:       io.NopCloser(bytes.NewBufferString(tt.body)),
+		}, nil).Once()
+
+		badge, err := client.VerifyBadge("public-123")
+
+		assert.ErrorIs(t, err, tt.err)
+		if tt.err == ErrBadgeRevoked {
+			assert.Equal(t, BadgeStateRevoked, badge.State)
+		}
+	}
+}
+
+func TestBadgeAssertion_Issuer(t *testing.T) {
+	for _, tt := range []struct {
+		body   string
+		issuer string
+	}{
+		{body: `{"badge": {"issuer": {"id": "https://api.credly.com/v1/obi/v2/issuers/org-456"}}}`, issuer: "org-456"},
+		{body: `{"badge": {"issuer": "https://api.credly.com/v1/obi/v2/issuers/org-456/"}}`, issuer: "org-456"},
+		{body: `{"badge": "https://api.credly.com/v1/obi/v2/badge_classes/class-123"}`, issuer: ""},
+		{body: `{}`, issuer: ""},
+	} {
+		var a badgeAssertion
+		err := json.Unmarshal([]byte(tt.body), &a)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.issuer, a.Badge.Issuer.Id, tt.body)
+	}
+}
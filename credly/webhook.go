@@ -0,0 +1,131 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader is the request header carrying the signature of a
+// webhook event: the hex-encoded HMAC-SHA256 of the request body, keyed with
+// the webhook secret, optionally prefixed with "sha256=".
+const WebhookSignatureHeader = "X-Credly-Signature"
+
+// Webhook event types, see WebhookEvent.EventType.
+const (
+	WebhookBadgeCreated      = "badge.created"
+	WebhookBadgeStateChanged = "badge.state_changed"
+)
+
+// ErrInvalidSignature is returned by ParseWebhook when the signature of a
+// webhook request is missing or doesn't match its body.
+var ErrInvalidSignature = errors.New("Invalid webhook signature")
+
+// maxWebhookBodySize bounds how much of a webhook request body is read.
+const maxWebhookBodySize = 1 << 20
+
+// WebhookEvent represents an event sent by Credly to a webhook endpoint, e.g.
+// when a recipient accepts a badge.
+type WebhookEvent struct {
+	Id             string    `json:"id"`
+	OrganizationId string    `json:"organization_id"`
+	EventType      string    `json:"event_type"`
+	OccurredAt     time.Time `json:"occurred_at"`
+
+	// Badge is the badge the event is about. Its State is the state after the
+	// event, e.g. BadgeStateAccepted or BadgeStateRevoked.
+	Badge BadgeInfo `json:"badge"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, accepting
+// OccurredAt in any of the layouts Credly is known to use, see credlyTime.
+func (e *WebhookEvent) UnmarshalJSON(data []byte) error {
+	type webhookEvent WebhookEvent
+	var aux struct {
+		webhookEvent
+		OccurredAt credlyTime `json:"occurred_at"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	decoded := aux.webhookEvent
+	decoded.OccurredAt = time.Time(aux.OccurredAt)
+
+	*e = WebhookEvent(decoded)
+	return nil
+}
+
+// ParseWebhook verifies the signature of a webhook request sent by Credly and
+// decodes its event. The request body is left readable by the caller.
+//
+// r: The incoming webhook request.
+// secret: The secret shared with Credly when registering the webhook.
+// Returns: The decoded WebhookEvent, ErrInvalidSignature if the signature is
+// missing or invalid, or another error if secret is empty or the body can't be
+// read or decoded.
+func ParseWebhook(r *http.Request, secret string) (WebhookEvent, error) {
+	var event WebhookEvent
+
+	// An empty key would let anyone compute a valid signature
+	if secret == "" {
+		return event, fmt.Errorf("[credly.ParseWebhook] Webhook secret must not be empty")
+	}
+
+	if r.Body == nil {
+		return event, fmt.Errorf("[credly.ParseWebhook] Empty request body")
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize+1))
+	if err != nil {
+		return event, fmt.Errorf("[credly.ParseWebhook] Failed to read request body: %v", err)
+	}
+	if len(body) > maxWebhookBodySize {
+		return event, fmt.Errorf("[credly.ParseWebhook] Request body exceeds %d bytes", maxWebhookBodySize)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !validWebhookSignature(body, r.Header.Get(WebhookSignatureHeader), secret) {
+		return event, fmt.Errorf("[credly.ParseWebhook] %w", ErrInvalidSignature)
+	}
+
+	if err := json.Unmarshal(body, &event); err != nil {
+		return event, fmt.Errorf("[credly.ParseWebhook] Failed to parse JSON data: %v", err)
+	}
+
+	return event, nil
+}
+
+// validWebhookSignature reports whether signature is the HMAC-SHA256 of body keyed with secret.
+func validWebhookSignature(body []byte, signature, secret string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Cisco Systems, Inc. and its affiliates
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credly
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// signWebhook returns the signature of a webhook body keyed with secret.
+func signWebhook(body, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseWebhook(t *testing.T) {
+	body := `{
+		"id": "event-123",
+		"organization_id": "org-123",
+		"event_type": "badge.state_changed",
+		"occurred_at": "2024-05-01T12:00:00Z",
+		"badge": {"id": "badge-123", "state": "accepted", "user": {"email": "test@example.com"}}
+	}`
+
+	for _, signature := range []string{signWebhook(body, "secret"), "sha256=" + signWebhook(body, "secret")} {
+		req := httptest.NewRequest("POST", "/webhooks/credly", bytes.NewBufferString(body))
+		req.Header.Set(WebhookSignatureHeader, signature)
+
+		event, err := ParseWebhook(req, "secret")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "event-123", event.Id)
+		assert.Equal(t, WebhookBadgeStateChanged, event.EventType)
+		assert.True(t, time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC).Equal(event.OccurredAt))
+		assert.Equal(t, "badge-123", event.Badge.Id)
+		assert.Equal(t, BadgeStateAccepted, event.Badge.State)
+		assert.Equal(t, "test@example.com", event.Badge.User.Email)
+
+		// The body can still be read by the caller
+		rest, _ := io.ReadAll(req.Body)
+		assert.Equal(t, body, string(rest))
+	}
+}
+
+func TestParseWebhook_InvalidSignature(t *testing.T) {
+	body := `{"id": "event-123"}`
+
+	for _, signature := range []string{"", "not-hex", signWebhook(body, "other-secret"), signWebhook(`{"id": "event-456"}`, "secret")} {
+		req := httptest.NewRequest("POST", "/webhooks/credly", bytes.NewBufferString(body))
+		req.Header.Set(WebhookSignatureHeader, signature)
+
+		_, err := ParseWebhook(req, "secret")
+
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+		assert.EqualError(t, err, "[credly.ParseWebhook] Invalid webhook signature")
+	}
+}
+
+func TestParseWebhook_InvalidJSON(t *testing.T) {
+	body := `{"id": `
+	req := httptest.NewRequest("POST", "/webhooks/credly", bytes.NewBufferString(body))
+	req.Header.Set(WebhookSignatureHeader, signWebhook(body, "secret"))
+
+	_, err := ParseWebhook(req, "secret")
+
+	assert.ErrorContains(t, err, "[credly.ParseWebhook] Failed to parse JSON data")
+	assert.NotErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestParseWebhook_EmptySecret(t *testing.T) {
+	body := `{"id": "event-123"}`
+	req := httptest.NewRequest("POST", "/webhooks/credly", bytes.NewBufferString(body))
+	req.Header.Set(WebhookSignatureHeader, signWebhook(body, ""))
+
+	_, err := ParseWebhook(req, "")
+
+	assert.ErrorContains(t, err, "[credly.ParseWebhook] Webhook secret must not be empty")
+}
+
+func TestParseWebhook_OccurredAtLayout(t *testing.T) {
+	body := `{"id": "event-123", "occurred_at": "2024-05-01 12:00:00 -0200"}`
+	req := httptest.NewRequest("POST", "/webhooks/credly", bytes.NewBufferString(body))
+	req.Header.Set(WebhookSignatureHeader, signWebhook(body, "secret"))
+
+	event, err := ParseWebhook(req, "secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "event-123", event.Id)
+	assert.True(t, time.Date(2024, time.May, 1, 14, 0, 0, 0, time.UTC).Equal(event.OccurredAt))
+}